@@ -0,0 +1,64 @@
+package semanticmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/internal/converter"
+)
+
+// Parse converts an HTML string to a Markdown AST without rendering it.
+// Options that affect parsing (ExtractMainContent, IncludeMetaData,
+// ResolveURLs, element processors) apply here; the extracted metadata is
+// returned as a leading *MetaDataNode. The AST can be transformed and then
+// passed to Render. ConvertString is equivalent to Parse followed by Render
+// with the same options.
+func Parse(htmlStr string, opts *ConversionOptions) ([]Node, error) {
+	if htmlStr == "" {
+		return nil, fmt.Errorf("empty HTML input")
+	}
+	return ParseReader(strings.NewReader(htmlStr), opts)
+}
+
+// ParseReader converts HTML from an io.Reader to a Markdown AST.
+// See Parse for details.
+func ParseReader(r io.Reader, opts *ConversionOptions) ([]Node, error) {
+	if r == nil {
+		return nil, fmt.Errorf("nil reader provided")
+	}
+
+	effective, err := effectiveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseHTML(context.Background(), r, effective)
+	if err != nil {
+		return nil, err
+	}
+
+	return converter.BuildAST(context.Background(), doc, effective)
+}
+
+// Render converts a Markdown AST to a Markdown string.
+// Options that affect rendering (EscapeMode, IncludeMetaData, node renderers)
+// apply here. When RefifyURLs is enabled, URLs in the AST are refified in
+// place and the reference legend is stored in opts.URLMap.
+func Render(nodes []Node, opts *ConversionOptions) (string, error) {
+	effective, err := effectiveOptions(opts)
+	if err != nil {
+		return "", err
+	}
+
+	converter.ApplyRefification(nodes, effective)
+	result, err := converter.RenderContext(context.Background(), nodes, effective)
+	if err != nil {
+		return "", err
+	}
+
+	propagateURLMap(opts, effective)
+
+	return result, nil
+}
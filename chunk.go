@@ -0,0 +1,50 @@
+package semanticmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/internal/converter"
+)
+
+// ChunkString converts an HTML string to Markdown split into size-budgeted chunks.
+// Chunks break at headings and sectioning elements (section, article, nav, ...);
+// code blocks, tables and lists are never split. Each chunk carries its heading
+// breadcrumb and the page metadata.
+func ChunkString(htmlStr string, opts *ConversionOptions, chunkOpts *ChunkOptions) ([]Chunk, error) {
+	if htmlStr == "" {
+		return nil, fmt.Errorf("empty HTML input")
+	}
+	return ChunkReader(strings.NewReader(htmlStr), opts, chunkOpts)
+}
+
+// ChunkReader converts HTML from an io.Reader to Markdown split into chunks.
+// See ChunkString for details.
+func ChunkReader(r io.Reader, opts *ConversionOptions, chunkOpts *ChunkOptions) ([]Chunk, error) {
+	if r == nil {
+		return nil, fmt.Errorf("nil reader provided")
+	}
+
+	effective, err := effectiveOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parseHTML(context.Background(), r, effective)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := converter.BuildAST(context.Background(), doc, effective)
+	if err != nil {
+		return nil, err
+	}
+	converter.ApplyRefification(nodes, effective)
+	chunks := converter.ChunkNodes(nodes, effective, chunkOpts)
+
+	propagateURLMap(opts, effective)
+
+	return chunks, nil
+}
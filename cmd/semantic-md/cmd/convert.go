@@ -1,15 +1,20 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	semanticmd "github.com/thorstenpfister/semantic-markdown"
+	"github.com/thorstenpfister/semantic-markdown/types"
 )
 
 var (
@@ -21,8 +26,13 @@ var (
 	metadataMode string
 	refifyURLs   bool
 	domain       string
+	resolveURLs  bool
+	footnotes    bool
+	tableFormat  string
+	semantic     string
 	debugMode    bool
 	escapeMode   string
+	outputFormat string
 )
 
 var convertCmd = &cobra.Command{
@@ -39,7 +49,11 @@ Input sources (priority order):
   3. stdin: If no flags provided, reads from stdin
 
 Output destination:
-  --output: Write to file (default: stdout)`,
+  --output: Write to file (default: stdout)
+
+Output formats:
+  markdown: Rendered Markdown (default)
+  ast-json: The parsed AST as JSON, for caching and debugging`,
 	Run: runConvert,
 }
 
@@ -56,8 +70,13 @@ func init() {
 	convertCmd.Flags().BoolVarP(&trackColumns, "track-table-columns", "t", false, "Enable table column tracking")
 	convertCmd.Flags().StringVarP(&metadataMode, "include-meta-data", "m", "", "Include metadata (basic|extended)")
 	convertCmd.Flags().BoolVarP(&refifyURLs, "refify-urls", "r", false, "Convert URLs to references for token reduction")
-	convertCmd.Flags().StringVarP(&domain, "domain", "d", "", "Base domain for reference (stored but does not resolve relative URLs)")
+	convertCmd.Flags().StringVarP(&domain, "domain", "d", "", "Base domain for resolving relative URLs (defaults to --url)")
+	convertCmd.Flags().BoolVar(&resolveURLs, "resolve-urls", false, "Resolve relative URLs against <base href> or the base domain")
+	convertCmd.Flags().BoolVar(&footnotes, "footnotes", false, "Convert citation links and reference lists to footnotes")
+	convertCmd.Flags().StringVar(&tableFormat, "table-format", "pipe", "Table format (pipe|html|records|csv|json|auto)")
+	convertCmd.Flags().StringVar(&semantic, "semantic-preset", "default", "Semantic element rendering (default|minimal|structure)")
 	convertCmd.Flags().StringVar(&escapeMode, "escape-mode", "smart", "Escape mode (smart|disabled)")
+	convertCmd.Flags().StringVarP(&outputFormat, "format", "f", "markdown", "Output format (markdown|ast-json)")
 
 	// Debug flag
 	convertCmd.Flags().BoolVar(&debugMode, "debug", false, "Enable debug logging")
@@ -101,12 +120,22 @@ func runConvert(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	// Default the base domain to the fetched URL so relative URLs resolve against it
+	baseDomain := domain
+	if baseDomain == "" && urlSource != "" {
+		baseDomain = urlSource
+	}
+
 	// Build conversion options
 	opts := &semanticmd.ConversionOptions{
-		WebsiteDomain:             domain,
+		WebsiteDomain:             baseDomain,
+		ResolveURLs:               resolveURLs,
 		ExtractMainContent:        extractMain,
 		RefifyURLs:                refifyURLs,
 		EnableTableColumnTracking: trackColumns,
+		Footnotes:                 footnotes,
+		TableFormat:               semanticmd.TableFormat(strings.ToLower(tableFormat)),
+		SemanticPreset:            semanticmd.SemanticPreset(strings.ToLower(semantic)),
 		Debug:                     debugMode,
 	}
 
@@ -140,19 +169,27 @@ func runConvert(cmd *cobra.Command, args []string) {
 		}()
 	}
 
-	// Convert
-	markdown, err := semanticmd.ConvertString(htmlContent, opts)
-	if err != nil {
-		exitWithError("Conversion failed: %v", err)
+	if htmlContent == "" {
+		exitWithError("Conversion failed: empty HTML input")
 	}
 
-	if debugMode {
-		fmt.Fprintf(os.Stderr, "[DEBUG] Generated %d bytes of Markdown\n", len(markdown))
+	// Convert and write output
+	var render func(w io.Writer) error
+	switch strings.ToLower(outputFormat) {
+	case "markdown":
+		render = func(w io.Writer) error {
+			return semanticmd.ConvertTo(w, strings.NewReader(htmlContent), opts)
+		}
+	case "ast-json":
+		render = func(w io.Writer) error {
+			return writeASTJSON(w, htmlContent, opts)
+		}
+	default:
+		exitWithError("Invalid output format: %s (must be 'markdown' or 'ast-json')", outputFormat)
 	}
 
-	// Write output
-	if err := writeOutput(outputFile, markdown); err != nil {
-		exitWithError("Failed to write output: %v", err)
+	if err := writeOutput(outputFile, render); err != nil {
+		exitWithError("Conversion failed: %v", err)
 	}
 
 	if debugMode {
@@ -160,6 +197,28 @@ func runConvert(cmd *cobra.Command, args []string) {
 	}
 }
 
+// writeASTJSON parses HTML and writes the resulting AST as indented JSON
+func writeASTJSON(w io.Writer, htmlContent string, opts *semanticmd.ConversionOptions) error {
+	nodes, err := semanticmd.Parse(htmlContent, opts)
+	if err != nil {
+		return err
+	}
+
+	data, err := types.MarshalNodes(nodes)
+	if err != nil {
+		return fmt.Errorf("failed to encode AST: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return fmt.Errorf("failed to format AST: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
 // fetchURL fetches HTML content from a URL
 func fetchURL(url string) (string, error) {
 	if debugMode {
@@ -217,17 +276,65 @@ func readInput(path string) (string, error) {
 	return string(content), nil
 }
 
-// writeOutput writes Markdown content to a file or stdout
-func writeOutput(path string, content string) error {
+// writeOutput streams rendered content to a file or stdout. A file is
+// written through a temporary file in the same directory that replaces it
+// only once rendering succeeded, so a failed conversion leaves an existing
+// file untouched.
+func writeOutput(path string, render func(w io.Writer) error) error {
 	if path == "" {
-		// Write to stdout
-		_, err := fmt.Print(content)
-		return err
+		out := bufio.NewWriter(os.Stdout)
+		if err := render(out); err != nil {
+			return err
+		}
+		if err := out.Flush(); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
 	}
 
 	if debugMode {
 		fmt.Fprintf(os.Stderr, "[DEBUG] Writing to file: %s\n", path)
 	}
 
-	return os.WriteFile(path, []byte(content), 0644)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	out := bufio.NewWriter(tmp)
+	if err := render(out); err != nil {
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	committed = true
+
+	if debugMode {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Wrote %d bytes\n", info.Size())
+		}
+	}
+	return nil
 }
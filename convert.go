@@ -1,8 +1,11 @@
 package semanticmd
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/thorstenpfister/semantic-markdown/internal/converter"
@@ -13,29 +16,93 @@ import (
 // ConvertString converts an HTML string to Markdown.
 // Returns an error if the HTML cannot be parsed or if options are invalid.
 func ConvertString(htmlStr string, opts *ConversionOptions) (string, error) {
+	return ConvertStringContext(context.Background(), htmlStr, opts)
+}
+
+// ConvertStringContext converts an HTML string to Markdown, stopping early
+// with ctx.Err() if the context is canceled. See ConvertContext.
+func ConvertStringContext(ctx context.Context, htmlStr string, opts *ConversionOptions) (string, error) {
 	if htmlStr == "" {
 		return "", fmt.Errorf("empty HTML input")
 	}
-	return ConvertReader(strings.NewReader(htmlStr), opts)
+	return ConvertContext(ctx, strings.NewReader(htmlStr), opts)
 }
 
 // ConvertReader converts HTML from an io.Reader to Markdown.
 // Returns an error if the HTML cannot be parsed or if options are invalid.
 func ConvertReader(r io.Reader, opts *ConversionOptions) (string, error) {
+	return ConvertContext(context.Background(), r, opts)
+}
+
+// ConvertContext converts HTML from an io.Reader to Markdown, checking for
+// cancellation while reading, parsing and rendering. Errors wrap ctx.Err()
+// or one of the limit errors (ErrInputTooLarge, ErrMaxDepthExceeded,
+// ErrMaxNodesExceeded, ErrOutputTooLarge) so callers can use errors.Is.
+func ConvertContext(ctx context.Context, r io.Reader, opts *ConversionOptions) (string, error) {
 	if r == nil {
 		return "", fmt.Errorf("nil reader provided")
 	}
 
-	doc, err := html.Parse(r)
+	effective, err := effectiveOptions(opts)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := parseHTML(ctx, r, effective)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := converter.Convert(ctx, doc, effective)
+	if err != nil {
+		return "", fmt.Errorf("conversion failed: %w", err)
+	}
+
+	propagateURLMap(opts, effective)
+
+	return result, nil
+}
+
+// ConvertTo converts HTML from an io.Reader and streams the Markdown to w.
+// Blocks are written as soon as they are rendered instead of building the
+// whole document in memory. The output is identical to ConvertReader.
+// On error, w may have received a partial document.
+func ConvertTo(w io.Writer, r io.Reader, opts *ConversionOptions) error {
+	return ConvertToContext(context.Background(), w, r, opts)
+}
+
+// ConvertToContext is like ConvertTo with cancellation support.
+// See ConvertContext for the errors it returns.
+func ConvertToContext(ctx context.Context, w io.Writer, r io.Reader, opts *ConversionOptions) error {
+	if w == nil {
+		return fmt.Errorf("nil writer provided")
+	}
+	if r == nil {
+		return fmt.Errorf("nil reader provided")
+	}
+
+	effective, err := effectiveOptions(opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse HTML: %w", err)
+		return err
 	}
 
-	return convertNodeWithValidation(doc, opts)
+	doc, err := parseHTML(ctx, r, effective)
+	if err != nil {
+		return err
+	}
+
+	if err := converter.ConvertTo(ctx, w, doc, effective); err != nil {
+		return fmt.Errorf("conversion failed: %w", err)
+	}
+
+	propagateURLMap(opts, effective)
+
+	return nil
 }
 
 // ConvertNode converts an html.Node tree to Markdown.
-// Panics if the node is nil. Use ConvertNodeSafe for error handling.
+// Panics if the node is nil or a configured limit is exceeded.
+// Use ConvertNodeSafe for error handling.
 func ConvertNode(node *html.Node, opts *ConversionOptions) string {
 	if node == nil {
 		panic("nil html.Node provided to ConvertNode")
@@ -43,7 +110,7 @@ func ConvertNode(node *html.Node, opts *ConversionOptions) string {
 
 	result, err := convertNodeWithValidation(node, opts)
 	if err != nil {
-		// Should not happen with valid options
+		// Should not happen with valid options and no limits
 		panic(fmt.Sprintf("unexpected error in ConvertNode: %v", err))
 	}
 
@@ -62,6 +129,32 @@ func ConvertNodeSafe(node *html.Node, opts *ConversionOptions) (string, error) {
 // convertNodeWithValidation validates options and performs conversion.
 // Works on a shallow copy to avoid mutating the caller's options.
 func convertNodeWithValidation(node *html.Node, opts *ConversionOptions) (string, error) {
+	effective, err := effectiveOptions(opts)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := converter.Convert(context.Background(), node, effective)
+	if err != nil {
+		return "", fmt.Errorf("conversion failed: %w", err)
+	}
+
+	propagateURLMap(opts, effective)
+
+	return result, nil
+}
+
+// parseHTML parses HTML from r, enforcing MaxInputBytes and cancellation.
+func parseHTML(ctx context.Context, r io.Reader, opts *ConversionOptions) (*html.Node, error) {
+	doc, err := html.Parse(converter.LimitReader(ctx, r, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+	return doc, nil
+}
+
+// effectiveOptions returns a validated shallow copy of opts with defaults applied.
+func effectiveOptions(opts *ConversionOptions) (*ConversionOptions, error) {
 	var effective ConversionOptions
 	if opts != nil {
 		effective = *opts
@@ -69,7 +162,7 @@ func convertNodeWithValidation(node *html.Node, opts *ConversionOptions) (string
 
 	// Validate and apply defaults
 	if err := validateOptions(&effective); err != nil {
-		return "", fmt.Errorf("invalid conversion options: %w", err)
+		return nil, fmt.Errorf("invalid conversion options: %w", err)
 	}
 
 	// Initialize URLMap for refification
@@ -77,14 +170,14 @@ func convertNodeWithValidation(node *html.Node, opts *ConversionOptions) (string
 		effective.URLMap = make(map[string]string)
 	}
 
-	result := converter.Convert(node, &effective)
+	return &effective, nil
+}
 
-	// Propagate URLMap back so callers can access the reference legend
+// propagateURLMap copies the URLMap back so callers can access the reference legend.
+func propagateURLMap(opts, effective *ConversionOptions) {
 	if opts != nil && effective.RefifyURLs {
 		opts.URLMap = effective.URLMap
 	}
-
-	return result, nil
 }
 
 // validateOptions checks that conversion options are valid
@@ -110,5 +203,123 @@ func validateOptions(opts *ConversionOptions) error {
 		return fmt.Errorf("invalid EscapeMode value: %q (must be 'smart' or 'disabled')", opts.EscapeMode)
 	}
 
+	// Apply default table span mode
+	if opts.TableSpans == "" {
+		opts.TableSpans = types.TableSpanAnnotate
+	}
+
+	// Validate table span mode
+	switch opts.TableSpans {
+	case types.TableSpanAnnotate, types.TableSpanDuplicate, types.TableSpanEmpty:
+		// Valid
+	default:
+		return fmt.Errorf("invalid TableSpans value: %q (must be 'annotate', 'duplicate' or 'empty')", opts.TableSpans)
+	}
+
+	// Apply default table format
+	if opts.TableFormat == "" {
+		opts.TableFormat = types.TableFormatPipe
+	}
+
+	// Validate table format
+	switch opts.TableFormat {
+	case types.TableFormatPipe, types.TableFormatHTML, types.TableFormatRecords,
+		types.TableFormatCSV, types.TableFormatJSON, types.TableFormatAuto:
+		// Valid
+	default:
+		return fmt.Errorf("invalid TableFormat value: %q (must be 'pipe', 'html', 'records', 'csv', 'json' or 'auto')", opts.TableFormat)
+	}
+
+	// Apply default definition list style
+	if opts.DefinitionListStyle == "" {
+		opts.DefinitionListStyle = types.DefinitionListBold
+	}
+
+	// Validate definition list style
+	switch opts.DefinitionListStyle {
+	case types.DefinitionListBold, types.DefinitionListExtra:
+		// Valid
+	default:
+		return fmt.Errorf("invalid DefinitionListStyle value: %q (must be 'bold' or 'extra')", opts.DefinitionListStyle)
+	}
+
+	// Apply default figure caption style
+	if opts.FigureCaptionStyle == "" {
+		opts.FigureCaptionStyle = types.FigureCaptionItalic
+	}
+
+	// Validate figure caption style
+	switch opts.FigureCaptionStyle {
+	case types.FigureCaptionItalic, types.FigureCaptionAlt:
+		// Valid
+	default:
+		return fmt.Errorf("invalid FigureCaptionStyle value: %q (must be 'italic' or 'alt')", opts.FigureCaptionStyle)
+	}
+
+	// Apply default details style
+	if opts.DetailsStyle == "" {
+		opts.DetailsStyle = types.DetailsHTML
+	}
+
+	// Validate details style
+	switch opts.DetailsStyle {
+	case types.DetailsHTML, types.DetailsBold, types.DetailsHeading:
+		// Valid
+	default:
+		return fmt.Errorf("invalid DetailsStyle value: %q (must be 'html', 'bold' or 'heading')", opts.DetailsStyle)
+	}
+
+	// Apply default semantic preset
+	if opts.SemanticPreset == "" {
+		opts.SemanticPreset = types.SemanticPresetDefault
+	}
+
+	// Validate semantic preset and element policies
+	switch opts.SemanticPreset {
+	case types.SemanticPresetDefault, types.SemanticPresetMinimal, types.SemanticPresetStructure:
+		// Valid
+	default:
+		return fmt.Errorf("invalid SemanticPreset value: %q (must be 'default', 'minimal' or 'structure')", opts.SemanticPreset)
+	}
+	if len(opts.SemanticElements) > 0 {
+		// Copy with lowercase keys, leaving the caller's map alone
+		elements := make(map[string]types.SemanticPolicy, len(opts.SemanticElements))
+		for tag, policy := range opts.SemanticElements {
+			lower := strings.ToLower(tag)
+			if _, ok := converter.SemanticTags[lower]; !ok {
+				return fmt.Errorf("invalid SemanticElements tag: %q (must be one of %s)", tag, strings.Join(slices.Sorted(maps.Keys(converter.SemanticTags)), ", "))
+			}
+			if other, ok := elements[lower]; ok && other != policy {
+				return fmt.Errorf("conflicting SemanticElements policies for %q", lower)
+			}
+			elements[lower] = policy
+		}
+		opts.SemanticElements = elements
+	}
+	for tag, policy := range opts.SemanticElements {
+		switch policy {
+		case types.SemanticUnwrap, types.SemanticComment, types.SemanticRule,
+			types.SemanticDrop, types.SemanticHeading, types.SemanticBlockquote:
+			// Valid
+		default:
+			if !strings.Contains(string(policy), "{content}") {
+				return fmt.Errorf("invalid SemanticElements policy for %q: %q (must be 'unwrap', 'comment', 'rule', 'drop', 'heading', 'blockquote' or a template containing {content})", tag, policy)
+			}
+		}
+	}
+
+	// Apply default script style
+	if opts.ScriptStyle == "" {
+		opts.ScriptStyle = types.ScriptStyleCaret
+	}
+
+	// Validate script style
+	switch opts.ScriptStyle {
+	case types.ScriptStyleCaret, types.ScriptStyleHTML, types.ScriptStyleUnicode:
+		// Valid
+	default:
+		return fmt.Errorf("invalid ScriptStyle value: %q (must be 'caret', 'html' or 'unicode')", opts.ScriptStyle)
+	}
+
 	return nil
 }
@@ -22,6 +22,15 @@
 //	}
 //	markdown, err := semanticmd.ConvertString(html, opts)
 //
+// # Working with the AST
+//
+// Parse and Render split conversion into two steps so the AST can be
+// transformed in between:
+//
+//	nodes, err := semanticmd.Parse(html, opts)
+//	// ... drop nodes, rewrite links, inject CustomNodes ...
+//	markdown, err := semanticmd.Render(nodes, opts)
+//
 // # Features
 //
 //   - Semantic HTML preservation (article, section, nav, etc.)
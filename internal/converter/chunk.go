@@ -0,0 +1,161 @@
+package converter
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
+
+// ChunkNodes splits an AST into rendered chunks along heading and sectioning
+// element boundaries. Top-level blocks (code, tables, lists, ...) and runs of
+// inline content forming a paragraph are never split.
+func ChunkNodes(nodes []types.Node, opts *types.ConversionOptions, chunkOpts *types.ChunkOptions) []types.Chunk {
+	c := &chunker{
+		opts:    opts,
+		maxSize: types.DefaultChunkSize,
+		size:    utf8.RuneCountInString,
+		meta:    findMeta(nodes),
+	}
+	if chunkOpts != nil {
+		if chunkOpts.MaxSize > 0 {
+			c.maxSize = chunkOpts.MaxSize
+		}
+		if chunkOpts.SizeFunc != nil {
+			c.size = chunkOpts.SizeFunc
+		}
+		if chunkOpts.IncludeFrontmatter && c.meta != nil {
+			c.frontmatter = renderMetadata(c.meta, opts)
+		}
+	}
+	c.budget = c.maxSize - c.size(c.frontmatter)
+
+	debugLog(opts, "Chunking AST (budget: %d)", c.budget)
+	c.walk(nodes)
+	c.flush()
+	debugLog(opts, "Created %d chunks", len(c.chunks))
+
+	return c.chunks
+}
+
+type headingEntry struct {
+	level int
+	text  string
+}
+
+type chunker struct {
+	opts        *types.ConversionOptions
+	maxSize     int
+	budget      int
+	size        func(string) int
+	meta        *types.MetaDataNode
+	frontmatter string
+
+	headings []headingEntry // current heading breadcrumb
+
+	current         []types.Node
+	currentSize     int
+	currentHeadings []string
+
+	chunks []types.Chunk
+}
+
+func (c *chunker) walk(nodes []types.Node) {
+	// Consecutive inline nodes form one paragraph and are measured together
+	var inline []types.Node
+	addInline := func() {
+		if len(inline) > 0 {
+			c.add(inline...)
+			inline = nil
+		}
+	}
+
+	for _, node := range nodes {
+		if !isBlockNode(node, c.opts) {
+			// Metadata is carried on every chunk instead of rendered inline
+			if _, ok := node.(*types.MetaDataNode); !ok {
+				inline = append(inline, node)
+			}
+			continue
+		}
+		addInline()
+
+		switch n := node.(type) {
+		case *types.HeadingNode:
+			c.flush()
+			for len(c.headings) > 0 && c.headings[len(c.headings)-1].level >= n.Level {
+				c.headings = c.headings[:len(c.headings)-1]
+			}
+			c.headings = append(c.headings, headingEntry{level: n.Level, text: plainText(n.Content)})
+			c.add(n)
+		case *types.SemanticHTMLNode:
+			if _, ok := sectioningTags[n.HTMLType]; !ok {
+				c.add(n)
+				continue
+			}
+			// Headings inside a sectioning element go out of scope when it ends
+			c.flush()
+			depth := len(c.headings)
+			c.walk(n.Content)
+			c.flush()
+			c.headings = c.headings[:depth]
+		default:
+			c.add(node)
+		}
+	}
+	addInline()
+}
+
+// add appends a unit of nodes that must stay together to the current chunk,
+// starting a new chunk if it would exceed the budget.
+func (c *chunker) add(nodes ...types.Node) {
+	size := c.size(Render(nodes, c.opts))
+	if len(c.current) > 0 && c.currentSize+size > c.budget {
+		c.flush()
+	}
+
+	if len(c.current) == 0 {
+		c.currentHeadings = make([]string, len(c.headings))
+		for i, h := range c.headings {
+			c.currentHeadings[i] = h.text
+		}
+	}
+	c.current = append(c.current, nodes...)
+	c.currentSize += size
+}
+
+func (c *chunker) flush() {
+	if len(c.current) == 0 {
+		return
+	}
+
+	content := Render(c.current, c.opts)
+	if strings.TrimSpace(content) != "" {
+		c.chunks = append(c.chunks, types.Chunk{
+			Content:  c.frontmatter + content,
+			Headings: c.currentHeadings,
+			Metadata: c.meta,
+		})
+	}
+
+	c.current = nil
+	c.currentSize = 0
+	c.currentHeadings = nil
+}
+
+// plainText extracts the unformatted text of inline content.
+func plainText(nodes []types.Node) string {
+	var buf strings.Builder
+	types.Inspect(nodes, func(node types.Node) bool {
+		switch n := node.(type) {
+		case *types.TextNode:
+			buf.WriteString(n.Content)
+		case *types.CodeNode:
+			buf.WriteString(n.Content)
+		case *types.MathNode:
+			buf.WriteString(n.TeX)
+		}
+		return true
+	})
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,182 @@
+package converter
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+	"golang.org/x/net/html"
+)
+
+// codeLanguageContainerDepth is how many ancestors of a <pre> are checked
+// for highlighter classes (e.g. GitHub's <div class="highlight-source-go">
+// or Sphinx's <div class="highlight-python"><div class="highlight"><pre>).
+const codeLanguageContainerDepth = 3
+
+// highlighterMarkers are classes that say the element is highlighted code,
+// so that another class on it names the language (e.g. highlight.js's
+// "hljs python" or Pandoc's "sourceCode haskell").
+var highlighterMarkers = map[string]struct{}{
+	"hljs": {}, "sourcecode": {}, "highlight": {}, "code-block": {},
+}
+
+// nonLanguageClasses are classes found next to highlighter markers that
+// don't name a language.
+var nonLanguageClasses = map[string]struct{}{
+	"hljs": {}, "sourcecode": {}, "highlight": {}, "code-block": {}, "code": {},
+	"notranslate": {}, "line-numbers": {}, "numberlines": {}, "wrap": {}, "copy": {},
+	"chroma": {}, "shiki": {}, "prettyprint": {}, "linenums": {}, "highlighter-rouge": {},
+}
+
+// plainLanguages mean "no language" and are dropped.
+var plainLanguages = map[string]struct{}{
+	"none": {}, "nohighlight": {}, "plaintext": {}, "plain": {}, "text": {}, "txt": {},
+}
+
+// detectCodeLanguage returns the language of a code block: the custom
+// DetectCodeLanguage result if any, then the highlighter conventions found
+// on <code>, <pre> and its containers, then, if enabled, a guess from the content.
+func detectCodeLanguage(pre, code *html.Node, content string, opts *types.ConversionOptions) string {
+	if opts.DetectCodeLanguage != nil {
+		if language := opts.DetectCodeLanguage(pre, content); language != "" {
+			return language
+		}
+	}
+
+	candidates := []*html.Node{}
+	if code != nil {
+		candidates = append(candidates, code)
+	}
+	n := pre
+	for i := 0; i <= codeLanguageContainerDepth && n != nil && n.Type == html.ElementNode; i++ {
+		candidates = append(candidates, n)
+		n = n.Parent
+	}
+	for _, candidate := range candidates {
+		if language := elementLanguage(candidate); language != "" {
+			return language
+		}
+	}
+
+	if opts.GuessCodeLanguage {
+		return guessCodeLanguage(content)
+	}
+	return ""
+}
+
+// elementLanguage reads a language from the data-lang/data-language
+// attributes or the class of a single element.
+func elementLanguage(node *html.Node) string {
+	for _, attr := range []string{"data-lang", "data-language"} {
+		if language := normalizeLanguage(getAttribute(node, attr)); language != "" {
+			return language
+		}
+	}
+
+	classes := strings.Fields(strings.ToLower(getAttribute(node, "class")))
+	marked := false
+	for i, class := range classes {
+		for _, prefix := range []string{"language-", "lang-", "highlight-source-", "highlight-", "sourcecode-"} {
+			if language, ok := strings.CutPrefix(class, prefix); ok {
+				if language = normalizeLanguage(language); language != "" {
+					return language
+				}
+			}
+		}
+		// SyntaxHighlighter: class="brush: js"
+		if class == "brush:" && i+1 < len(classes) {
+			return normalizeLanguage(strings.TrimSuffix(classes[i+1], ";"))
+		}
+		if language, ok := strings.CutPrefix(class, "brush:"); ok {
+			return normalizeLanguage(strings.TrimSuffix(language, ";"))
+		}
+		if _, ok := highlighterMarkers[class]; ok {
+			marked = true
+		}
+	}
+
+	if marked {
+		for _, class := range classes {
+			// Skip utility classes such as "js-file-line"
+			if _, ok := nonLanguageClasses[class]; !ok && !strings.ContainsAny(class, "-_") {
+				return normalizeLanguage(class)
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeLanguage lowercases a language name and drops names meaning
+// plain text.
+func normalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if _, plain := plainLanguages[language]; plain {
+		return ""
+	}
+	return language
+}
+
+// languagePattern guesses a language when its pattern matches the content.
+type languagePattern struct {
+	language string
+	pattern  *regexp.Regexp
+}
+
+// languagePatterns are checked in order; more specific patterns come first.
+var languagePatterns = []languagePattern{
+	{"php", regexp.MustCompile(`^<\?php`)},
+	{"html", regexp.MustCompile(`(?i)^<!doctype html|^<html[\s>]`)},
+	{"xml", regexp.MustCompile(`^<\?xml`)},
+	{"go", regexp.MustCompile(`(?m)^package \w+$|^func (\(\w+ \*?\w+\) )?\w+\(`)},
+	{"rust", regexp.MustCompile(`(?m)^\s*(pub )?fn \w+.*\{$|^use \w+::`)},
+	{"python", regexp.MustCompile(`(?m)^\s*def \w+\(.*\):\s*$|^\s*from [\w.]+ import |^import \w+$|^\s*class \w+(\(.*\))?:\s*$`)},
+	{"java", regexp.MustCompile(`(?m)^\s*public (static )?(class|void|interface) `)},
+	{"c", regexp.MustCompile(`(?m)^#include\s*[<"]`)},
+	{"sql", regexp.MustCompile(`(?is)^\s*(select\s.+\sfrom\s|insert\s+into\s|update\s+\w+\s+set\s|create\s+(table|index|view)\s)`)},
+	{"javascript", regexp.MustCompile(`(?m)^\s*(const|let|var) \w+ = |^\s*function \w*\(|=> \{|console\.log\(|^\s*(import|export) .* from ['"]`)},
+	{"css", regexp.MustCompile(`(?m)^[\w.#:\-\[\]="\s,>*]+\{\s*$\n?^\s*[\w-]+\s*:`)},
+	{"bash", regexp.MustCompile(`(?m)^\$ \w|^\s*(sudo|apt-get|apt|brew|npm|yarn|pip|go|git|cd|echo|export|curl|docker|kubectl) [\w-]`)},
+	{"yaml", regexp.MustCompile(`(?m)^---\s*$|^[\w-]+:\s*$\n^\s+[\w-]+:`)},
+}
+
+// guessCodeLanguage guesses the language of code from shebangs and
+// characteristic syntax. Returns "" when unsure.
+func guessCodeLanguage(content string) string {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return ""
+	}
+
+	if shebang, ok := strings.CutPrefix(content, "#!"); ok {
+		line, _, _ := strings.Cut(shebang, "\n")
+		fields := strings.Fields(line)
+		if len(fields) > 0 {
+			interpreter := fields[0][strings.LastIndex(fields[0], "/")+1:]
+			if interpreter == "env" && len(fields) > 1 {
+				interpreter = fields[1]
+			}
+			switch {
+			case strings.HasPrefix(interpreter, "python"):
+				return "python"
+			case interpreter == "node":
+				return "javascript"
+			case interpreter == "sh" || interpreter == "bash" || interpreter == "zsh":
+				return "bash"
+			case interpreter != "":
+				return interpreter
+			}
+		}
+	}
+
+	if (strings.HasPrefix(content, "{") || strings.HasPrefix(content, "[")) && json.Valid([]byte(content)) {
+		return "json"
+	}
+
+	for _, p := range languagePatterns {
+		if p.pattern.MatchString(content) {
+			return p.language
+		}
+	}
+	return ""
+}
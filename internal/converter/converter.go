@@ -2,8 +2,11 @@
 package converter
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/thorstenpfister/semantic-markdown/types"
 	"golang.org/x/net/html"
@@ -17,9 +20,44 @@ func debugLog(opts *types.ConversionOptions, format string, args ...interface{})
 }
 
 // Convert is the main conversion function that orchestrates parsing and rendering.
-func Convert(node *html.Node, opts *types.ConversionOptions) string {
+// It checks for cancellation and enforces the configured limits.
+func Convert(ctx context.Context, node *html.Node, opts *types.ConversionOptions) (string, error) {
+	var buf strings.Builder
+	if err := ConvertTo(ctx, &buf, node, opts); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ConvertTo is like Convert but streams the rendered Markdown to w.
+func ConvertTo(ctx context.Context, w io.Writer, node *html.Node, opts *types.ConversionOptions) error {
 	debugLog(opts, "Starting HTML to Markdown conversion")
 
+	nodes, err := BuildAST(ctx, node, opts)
+	if err != nil {
+		return err
+	}
+	ApplyRefification(nodes, opts)
+
+	// Render AST to Markdown
+	debugLog(opts, "Rendering AST to Markdown")
+	counter := &countingWriter{w: w}
+	if err := RenderTo(ctx, counter, nodes, opts); err != nil {
+		return err
+	}
+	debugLog(opts, "Conversion complete, generated %d bytes", counter.n)
+
+	return nil
+}
+
+// BuildAST runs the parsing half of the pipeline: limit checks, metadata
+// extraction, main content detection, parsing, footnote linking and URL resolution.
+func BuildAST(ctx context.Context, node *html.Node, opts *types.ConversionOptions) ([]types.Node, error) {
+	// Enforce limits before any recursive processing
+	if err := checkLimits(ctx, node, opts); err != nil {
+		return nil, err
+	}
+
 	// Extract metadata from <head> if requested
 	var metaNode *types.MetaDataNode
 	if opts.IncludeMetaData != types.MetaDataNone {
@@ -47,25 +85,43 @@ func Convert(node *html.Node, opts *types.ConversionOptions) string {
 
 	// Parse HTML to AST
 	debugLog(opts, "Parsing HTML to AST")
-	nodes := Parse(root, opts)
+	nodes := Parse(ctx, root, opts)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	debugLog(opts, "Parsed %d top-level AST nodes", len(nodes))
 
+	// Turn citation links into footnote references
+	if opts.Footnotes {
+		nodes = LinkFootnotes(nodes)
+	}
+
 	// Prepend metadata node if we extracted any
 	if metaNode != nil {
 		nodes = append([]types.Node{metaNode}, nodes...)
 	}
 
-	// Apply URL refification if requested
-	if opts.RefifyURLs {
-		debugLog(opts, "Refifying URLs")
-		opts.URLMap = RefifyURLs(nodes)
-		debugLog(opts, "Created %d URL references", len(opts.URLMap))
+	// Resolve relative URLs before refification so absolute URLs can be shortened
+	if opts.ResolveURLs {
+		base := FindBaseURL(node, opts.WebsiteDomain)
+		if base != nil {
+			debugLog(opts, "Resolving relative URLs against %s", base)
+			ResolveURLs(nodes, base)
+		} else {
+			debugLog(opts, "No base URL available, relative URLs left as-is")
+		}
 	}
 
-	// Render AST to Markdown
-	debugLog(opts, "Rendering AST to Markdown")
-	result := Render(nodes, opts)
-	debugLog(opts, "Conversion complete, generated %d bytes", len(result))
+	return nodes, ctx.Err()
+}
 
-	return result
+// ApplyRefification refifies URLs in place and stores the reference legend
+// in opts.URLMap, if RefifyURLs is enabled. Must run before rendering.
+func ApplyRefification(nodes []types.Node, opts *types.ConversionOptions) {
+	if !opts.RefifyURLs {
+		return
+	}
+	debugLog(opts, "Refifying URLs")
+	opts.URLMap = RefifyURLs(nodes)
+	debugLog(opts, "Created %d URL references", len(opts.URLMap))
 }
@@ -0,0 +1,140 @@
+package converter
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+	"golang.org/x/net/html"
+)
+
+// isFootnoteList reports whether a <ol>/<ul> holds footnote or reference
+// definitions, judging by the list itself and its immediate container:
+//   - Wikipedia: <ol class="references">
+//   - Pandoc, kramdown: <section class="footnotes" role="doc-endnotes"><ol>
+//   - Python-Markdown: <div class="footnote"><ol>
+func isFootnoteList(node *html.Node) bool {
+	for n := node; n != nil && n.Type == html.ElementNode; n = n.Parent {
+		switch getAttribute(n, "role") {
+		case "doc-endnotes", "doc-footnotes", "doc-bibliography":
+			return true
+		}
+		for _, class := range strings.Fields(getAttribute(n, "class")) {
+			if class == "references" || strings.HasPrefix(class, "footnote") {
+				return true
+			}
+		}
+		if n != node {
+			break
+		}
+	}
+	return false
+}
+
+// isFootnoteBacklink reports whether node links from a footnote back to its
+// citation (e.g. Wikipedia's "^" or Pandoc's "↩"). These are dropped in
+// footnote mode since GFM footnotes provide their own back-references.
+func isFootnoteBacklink(node *html.Node) bool {
+	if getAttribute(node, "role") == "doc-backlink" {
+		return true
+	}
+	for _, class := range strings.Fields(getAttribute(node, "class")) {
+		if strings.Contains(class, "backlink") || strings.Contains(class, "backref") ||
+			class == "footnote-back" || class == "reversefootnote" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseFootnoteList parses each <li> of a references list into a FootnoteNode.
+// Labels are assigned later by LinkFootnotes.
+func parseFootnoteList(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
+	var result []types.Node
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && strings.ToLower(child.Data) == "li" {
+			result = append(result, &types.FootnoteNode{
+				ID:      footnoteAnchor(child),
+				Content: parseNode(ctx, child, opts, indentLevel),
+			})
+		}
+	}
+
+	return result
+}
+
+// footnoteAnchor returns the id of a footnote item, falling back to the
+// first id found inside it (some generators put the anchor on a child).
+func footnoteAnchor(node *html.Node) string {
+	if id := getAttribute(node, "id"); id != "" {
+		return id
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			if id := footnoteAnchor(child); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+// LinkFootnotes numbers footnote definitions in document order and replaces
+// fragment links that target them with FootnoteReferenceNodes. A superscript
+// that only wraps citations (e.g. <sup>[<a href="#fn1">1</a>]</sup>) is
+// replaced by the references themselves.
+// NOTE: Links to fragments outside a references list are left untouched.
+func LinkFootnotes(nodes []types.Node) []types.Node {
+	labels := make(map[string]string)
+	count := 0
+	types.Inspect(nodes, func(node types.Node) bool {
+		if n, ok := node.(*types.FootnoteNode); ok {
+			count++
+			n.Label = strconv.Itoa(count)
+			if _, seen := labels[n.ID]; n.ID != "" && !seen {
+				labels[n.ID] = n.Label
+			}
+		}
+		return true
+	})
+	if len(labels) == 0 {
+		return nodes
+	}
+
+	return types.Rewrite(nodes, func(node types.Node) []types.Node {
+		switch n := node.(type) {
+		case *types.LinkNode:
+			if id, ok := strings.CutPrefix(strings.TrimSpace(n.Href), "#"); ok {
+				if label, ok := labels[id]; ok {
+					return []types.Node{&types.FootnoteReferenceNode{Label: label}}
+				}
+			}
+		case *types.SuperscriptNode:
+			if refs := citationRefs(n.Content); refs != nil {
+				return refs
+			}
+		}
+		return []types.Node{node}
+	})
+}
+
+// citationRefs returns the footnote references in content if it holds
+// nothing else but brackets, commas and whitespace; otherwise nil.
+func citationRefs(content []types.Node) []types.Node {
+	var refs []types.Node
+	for _, node := range content {
+		switch n := node.(type) {
+		case *types.FootnoteReferenceNode:
+			refs = append(refs, n)
+		case *types.TextNode:
+			if strings.Trim(n.Content, "[](), \t\n") != "" {
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+	return refs
+}
@@ -3,6 +3,7 @@ package converter
 import (
 	"strings"
 
+	"github.com/thorstenpfister/semantic-markdown/types"
 	"golang.org/x/net/html"
 )
 
@@ -21,6 +22,44 @@ var nonSemanticTagNames = map[string]struct{}{
 	"viewport": {}, "referrer": {}, "Content-Security-Policy": {},
 }
 
+// SemanticTags are the elements parsed as SemanticHTMLNode, whose rendering
+// SemanticPreset and SemanticElements control. <figcaption> and <summary>
+// only count outside <figure> and <details>.
+var SemanticTags = map[string]struct{}{
+	"article": {}, "section": {}, "aside": {}, "nav": {}, "header": {}, "footer": {}, "main": {},
+	"figcaption": {}, "summary": {}, "mark": {}, "time": {},
+}
+
+// sectioningTags are semantic containers whose boundaries split chunks.
+var sectioningTags = map[string]struct{}{
+	"article": {}, "aside": {}, "footer": {}, "header": {}, "main": {}, "nav": {}, "section": {},
+}
+
+// structurePolicies are the SemanticPresetStructure policies that differ
+// from its comment wrapper default.
+var structurePolicies = map[string]types.SemanticPolicy{
+	"aside": types.SemanticBlockquote,
+	"mark":  types.SemanticUnwrap,
+	"time":  types.SemanticUnwrap,
+}
+
+// inlineSemanticTags are semantic elements that stay inline when unwrapped.
+var inlineSemanticTags = map[string]struct{}{
+	"mark": {}, "time": {},
+}
+
+// Unicode forms for ScriptStyleUnicode.
+var superscriptRunes = map[rune]rune{
+	'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴', '5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+	'+': '⁺', '-': '⁻', '=': '⁼', '(': '⁽', ')': '⁾', 'i': 'ⁱ', 'n': 'ⁿ',
+}
+
+var subscriptRunes = map[rune]rune{
+	'0': '₀', '1': '₁', '2': '₂', '3': '₃', '4': '₄', '5': '₅', '6': '₆', '7': '₇', '8': '₈', '9': '₉',
+	'+': '₊', '-': '₋', '=': '₌', '(': '₍', ')': '₎',
+	'a': 'ₐ', 'e': 'ₑ', 'h': 'ₕ', 'k': 'ₖ', 'l': 'ₗ', 'm': 'ₘ', 'n': 'ₙ', 'o': 'ₒ', 'p': 'ₚ', 's': 'ₛ', 't': 'ₜ', 'x': 'ₓ',
+}
+
 var mediaSuffixes = map[string]struct{}{
 	"jpeg": {}, "jpg": {}, "png": {}, "gif": {}, "bmp": {}, "tiff": {}, "tif": {}, "svg": {},
 	"webp": {}, "ico": {}, "avi": {}, "mov": {}, "mp4": {}, "mkv": {}, "flv": {}, "wmv": {}, "webm": {}, "mpeg": {},
@@ -45,6 +84,17 @@ func findElement(node *html.Node, tag string) *html.Node {
 	return nil
 }
 
+// childElements returns the direct children of node with the given tag.
+func childElements(node *html.Node, tag string) []*html.Node {
+	var result []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && strings.ToLower(child.Data) == tag {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
 func findByAttribute(node *html.Node, attrKey, attrValue string) *html.Node {
 	if node.Type == html.ElementNode {
 		if val := getAttribute(node, attrKey); strings.Contains(val, attrValue) {
@@ -0,0 +1,256 @@
+package converter
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+	"golang.org/x/net/html"
+)
+
+// codeGutterClasses mark the line-number gutters that highlighters
+// (Pygments, Chroma, Prism, highlight.js, GitHub, Hexo) put next to the
+// code.
+var codeGutterClasses = map[string]struct{}{
+	"gutter": {}, "linenos": {}, "lineno": {}, "linenodiv": {}, "line-number": {},
+	"line-numbers-rows": {}, "blob-num": {}, "hljs-ln-numbers": {}, "hljs-ln-n": {},
+	"lnt": {}, "ln": {}, "ln-num": {},
+}
+
+// copyButtonClasses mark the copy buttons placed on code blocks.
+var copyButtonClasses = map[string]struct{}{
+	"copy": {}, "copy-button": {}, "copy-code-button": {}, "btn-copy": {}, "clipboard": {},
+}
+
+// codeTableClasses mark tables that highlighters lay code out in
+// (Pygments, Chroma, highlight.js).
+var codeTableClasses = map[string]struct{}{
+	"highlighttable": {}, "lntable": {}, "hljs-ln": {},
+}
+
+// codeCellClasses mark the table cells holding the code next to a gutter
+// (GitHub blob views, highlight.js).
+var codeCellClasses = map[string]struct{}{
+	"blob-code": {}, "blob-code-inner": {}, "hljs-ln-code": {},
+}
+
+// codeLineClasses mark elements holding one line of code (Shiki, Prism,
+// Docusaurus, Hexo, CodeMirror).
+var codeLineClasses = map[string]struct{}{
+	"line": {}, "token-line": {}, "code-line": {}, "cm-line": {}, "view-line": {},
+}
+
+// codeLineTags are elements that always hold lines of their own.
+var codeLineTags = map[string]struct{}{
+	"div": {}, "p": {}, "li": {}, "tr": {},
+}
+
+// codeText returns the text of a code element, dropping gutters and copy
+// buttons and turning <br> and line elements into line breaks.
+func codeText(node *html.Node) string {
+	var buf strings.Builder
+	writeCodeText(&buf, node)
+	return buf.String()
+}
+
+func writeCodeText(buf *strings.Builder, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case html.TextNode:
+			if isTableStructure(node) && strings.TrimSpace(child.Data) == "" {
+				// Markup indentation between rows and cells
+				continue
+			}
+			buf.WriteString(child.Data)
+		case html.ElementNode:
+			if isCodeChrome(child) {
+				continue
+			}
+			tag := strings.ToLower(child.Data)
+			if tag == "br" {
+				buf.WriteString("\n")
+				continue
+			}
+			// Start a line element on a new line unless the markup already does
+			if isCodeLine(child) && buf.Len() > 0 && !strings.HasSuffix(buf.String(), "\n") {
+				buf.WriteString("\n")
+			}
+			writeCodeText(buf, child)
+		}
+	}
+}
+
+// isCodeChrome reports whether node is a line-number gutter or a copy
+// button rather than code.
+func isCodeChrome(node *html.Node) bool {
+	switch strings.ToLower(node.Data) {
+	case "button", "clipboard-copy":
+		return true
+	}
+	return hasClassIn(node, codeGutterClasses) || hasClassIn(node, copyButtonClasses)
+}
+
+// hasClassIn reports whether any of node's classes is in set.
+func hasClassIn(node *html.Node, set map[string]struct{}) bool {
+	for _, class := range strings.Fields(strings.ToLower(getAttribute(node, "class"))) {
+		if _, ok := set[class]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isCopyButton reports whether node is a "Copy code" button on a code
+// block: it sits inside or next to a <pre>, and its class, aria-label,
+// title or text has "copy" as a word.
+func isCopyButton(node *html.Node) bool {
+	if !isNearCode(node) {
+		return false
+	}
+	if strings.ToLower(node.Data) == "clipboard-copy" || hasClassIn(node, copyButtonClasses) {
+		return true
+	}
+	for _, s := range []string{getAttribute(node, "class"), getAttribute(node, "aria-label"), getAttribute(node, "title"), getTextContent(node)} {
+		words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if slices.Contains(words, "copy") {
+			return true
+		}
+	}
+	return false
+}
+
+// isNearCode reports whether node is inside a <pre> or <code> element, or
+// next to a <pre>: a sibling of node or of its parent is or holds one, as
+// in the toolbars highlighters wrap code blocks in.
+func isNearCode(node *html.Node) bool {
+	for n := node.Parent; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode && (strings.ToLower(n.Data) == "pre" || strings.ToLower(n.Data) == "code") {
+			return true
+		}
+	}
+	for n, depth := node, 0; n.Parent != nil && depth < 2; n, depth = n.Parent, depth+1 {
+		for sibling := n.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+			if sibling != n && sibling.Type == html.ElementNode && findElement(sibling, "pre") != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isTableStructure reports whether node is a table element that holds
+// rows or cells rather than content.
+func isTableStructure(node *html.Node) bool {
+	switch strings.ToLower(node.Data) {
+	case "table", "thead", "tbody", "tfoot", "tr":
+		return true
+	}
+	return false
+}
+
+// isCodeLine reports whether node holds a line of code of its own.
+func isCodeLine(node *html.Node) bool {
+	if _, ok := codeLineTags[strings.ToLower(node.Data)]; ok {
+		return true
+	}
+	return hasClassIn(node, codeLineClasses)
+}
+
+// parseCodeTable parses a table that lays out highlighted code next to a
+// line-number gutter (Pygments "highlighttable", Chroma "lntable", Hexo,
+// GitHub blob views) as a code block. Returns nil for other tables.
+func parseCodeTable(node *html.Node, opts *types.ConversionOptions) *types.CodeNode {
+	if !isCodeTable(node) {
+		return nil
+	}
+
+	var lines []string
+	var code *html.Node
+	for _, row := range tableRowElements(node) {
+		cells := rowCellElements(row)
+		var texts []string
+		for i, cell := range cells {
+			if isCodeChrome(cell) {
+				continue
+			}
+			text := codeText(cell)
+			// Chroma's gutter cell holds nothing but line numbers
+			if i == 0 && len(cells) > 1 && strings.TrimFunc(text, func(r rune) bool { return unicode.IsDigit(r) || unicode.IsSpace(r) }) == "" {
+				continue
+			}
+			if code == nil {
+				code = findElement(cell, "code")
+				if code == nil {
+					code = findElement(cell, "pre")
+				}
+			}
+			texts = append(texts, text)
+		}
+		lines = append(lines, strings.Join(texts, ""))
+	}
+
+	content := strings.TrimSuffix(strings.Join(lines, "\n"), "\n")
+	return &types.CodeNode{
+		Content:  content,
+		Language: detectCodeLanguage(node, code, content, opts),
+	}
+}
+
+// isCodeTable reports whether a table holds highlighted code: it has a
+// highlighter table class, or a line-number gutter cell next to a cell
+// that holds code. Gutter classes alone are too common in layout tables
+// to go by.
+func isCodeTable(node *html.Node) bool {
+	if hasClassIn(node, codeTableClasses) {
+		return true
+	}
+	var gutter, code bool
+	for _, row := range tableRowElements(node) {
+		for _, cell := range rowCellElements(row) {
+			switch {
+			case strings.ToLower(cell.Data) == "th":
+				// Code tables have no header cells
+				return false
+			case hasClassIn(cell, codeGutterClasses):
+				gutter = true
+			case hasClassIn(cell, codeCellClasses) || findElement(cell, "pre") != nil || findElement(cell, "code") != nil:
+				code = true
+			}
+		}
+	}
+	return gutter && code
+}
+
+// rowCellElements returns the <td> and <th> elements of a table row.
+func rowCellElements(row *html.Node) []*html.Node {
+	var cells []*html.Node
+	for child := row.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch strings.ToLower(child.Data) {
+		case "td", "th":
+			cells = append(cells, child)
+		}
+	}
+	return cells
+}
+
+// tableRowElements returns the <tr> elements of a table, including those
+// inside <thead>, <tbody> and <tfoot>.
+func tableRowElements(table *html.Node) []*html.Node {
+	var rows []*html.Node
+	for child := table.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch strings.ToLower(child.Data) {
+		case "tr":
+			rows = append(rows, child)
+		case "thead", "tbody", "tfoot":
+			rows = append(rows, childElements(child, "tr")...)
+		}
+	}
+	return rows
+}
@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+	"golang.org/x/net/html"
+)
+
+// ctxCheckInterval is how many nodes are visited between cancellation checks.
+const ctxCheckInterval = 1024
+
+// LimitReader wraps r so reads fail once the context is done or more than
+// opts.MaxInputBytes have been read.
+func LimitReader(ctx context.Context, r io.Reader, opts *types.ConversionOptions) io.Reader {
+	return &limitedReader{ctx: ctx, r: r, max: opts.MaxInputBytes}
+}
+
+type limitedReader struct {
+	ctx  context.Context
+	r    io.Reader
+	max  int64
+	read int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if err := l.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.max > 0 && l.read > l.max {
+		return 0, fmt.Errorf("%w (limit %d bytes)", types.ErrInputTooLarge, l.max)
+	}
+	return n, err
+}
+
+// checkLimits iteratively walks the HTML tree to enforce MaxDepth and
+// MaxNodes before any recursive processing, checking for cancellation
+// along the way.
+func checkLimits(ctx context.Context, root *html.Node, opts *types.ConversionOptions) error {
+	type entry struct {
+		node  *html.Node
+		depth int
+	}
+
+	count := 0
+	stack := []entry{{root, 0}}
+	for len(stack) > 0 {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		count++
+		if count%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if opts.MaxNodes > 0 && count > opts.MaxNodes {
+			return fmt.Errorf("%w (limit %d nodes)", types.ErrMaxNodesExceeded, opts.MaxNodes)
+		}
+		if opts.MaxDepth > 0 && e.depth > opts.MaxDepth {
+			return fmt.Errorf("%w (limit %d levels)", types.ErrMaxDepthExceeded, opts.MaxDepth)
+		}
+
+		for child := e.node.FirstChild; child != nil; child = child.NextSibling {
+			stack = append(stack, entry{child, e.depth + 1})
+		}
+	}
+
+	return ctx.Err()
+}
@@ -0,0 +1,381 @@
+package converter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+	"golang.org/x/net/html"
+)
+
+// texEncodings are <annotation> encodings that hold the TeX source of a
+// MathML formula (KaTeX, MathJax, LaTeXML, Wikipedia).
+var texEncodings = map[string]struct{}{
+	"application/x-tex": {}, "application/x-latex": {}, "text/x-tex": {}, "tex": {}, "latex": {},
+}
+
+// mathJaxRenderClasses mark the output MathJax v2 renders next to the
+// <script type="math/tex"> holding the source, which is parsed instead.
+var mathJaxRenderClasses = map[string]struct{}{
+	"mathjax": {}, "mathjax_preview": {}, "mathjax_display": {}, "mathjax_chtml": {},
+	"mathjax_svg": {}, "mathjax_svg_display": {}, "mjx_assistive_mathml": {},
+}
+
+// texFunctions are operator names with a TeX command of their own.
+var texFunctions = map[string]struct{}{
+	"sin": {}, "cos": {}, "tan": {}, "cot": {}, "sec": {}, "csc": {},
+	"arcsin": {}, "arccos": {}, "arctan": {}, "sinh": {}, "cosh": {}, "tanh": {}, "coth": {},
+	"log": {}, "ln": {}, "lg": {}, "exp": {}, "lim": {}, "liminf": {}, "limsup": {},
+	"max": {}, "min": {}, "sup": {}, "inf": {}, "det": {}, "dim": {}, "ker": {},
+	"gcd": {}, "deg": {}, "arg": {}, "hom": {}, "Pr": {},
+}
+
+// texBigOperators take their MathML under/over scripts as limits.
+var texBigOperators = map[string]struct{}{
+	`\sum`: {}, `\prod`: {}, `\coprod`: {}, `\int`: {}, `\iint`: {}, `\oint`: {},
+	`\bigcup`: {}, `\bigcap`: {}, `\lim`: {}, `\max`: {}, `\min`: {}, `\sup`: {}, `\inf`: {},
+}
+
+// texAccents map MathML <mover>/<munder> marks to TeX accent commands.
+var texAccents = map[string]string{
+	"^": `\hat`, "ˆ": `\hat`, "~": `\tilde`, "˜": `\tilde`, "→": `\vec`, "⃗": `\vec`,
+	"˙": `\dot`, "¨": `\ddot`, "¯": `\overline`, "‾": `\overline`, "―": `\overline`,
+	"⏞": `\overbrace`,
+}
+
+var texUnderAccents = map[string]string{
+	"_": `\underline`, "̲": `\underline`, "‾": `\underline`, "⏟": `\underbrace`,
+}
+
+// texSymbols map MathML characters to TeX.
+var texSymbols = map[rune]string{
+	'α': `\alpha`, 'β': `\beta`, 'γ': `\gamma`, 'δ': `\delta`, 'ε': `\epsilon`, 'ϵ': `\epsilon`,
+	'ζ': `\zeta`, 'η': `\eta`, 'θ': `\theta`, 'ϑ': `\vartheta`, 'ι': `\iota`, 'κ': `\kappa`,
+	'λ': `\lambda`, 'μ': `\mu`, 'ν': `\nu`, 'ξ': `\xi`, 'π': `\pi`, 'ϖ': `\varpi`, 'ρ': `\rho`,
+	'ϱ': `\varrho`, 'σ': `\sigma`, 'ς': `\varsigma`, 'τ': `\tau`, 'υ': `\upsilon`, 'φ': `\phi`,
+	'ϕ': `\phi`, 'χ': `\chi`, 'ψ': `\psi`, 'ω': `\omega`,
+	'Γ': `\Gamma`, 'Δ': `\Delta`, 'Θ': `\Theta`, 'Λ': `\Lambda`, 'Ξ': `\Xi`, 'Π': `\Pi`,
+	'Σ': `\Sigma`, 'Υ': `\Upsilon`, 'Φ': `\Phi`, 'Ψ': `\Psi`, 'Ω': `\Omega`,
+	'±': `\pm`, '∓': `\mp`, '×': `\times`, '÷': `\div`, '·': `\cdot`, '⋅': `\cdot`, '∘': `\circ`,
+	'∗': `\ast`, '≤': `\leq`, '≥': `\geq`, '≠': `\neq`, '≈': `\approx`, '≡': `\equiv`,
+	'∼': `\sim`, '≃': `\simeq`, '≅': `\cong`, '∝': `\propto`, '≪': `\ll`, '≫': `\gg`,
+	'∞': `\infty`, '∂': `\partial`, '∇': `\nabla`, '∑': `\sum`, '∏': `\prod`, '∐': `\coprod`,
+	'∫': `\int`, '∬': `\iint`, '∮': `\oint`, '√': `\surd`,
+	'∈': `\in`, '∉': `\notin`, '∋': `\ni`, '⊂': `\subset`, '⊆': `\subseteq`, '⊃': `\supset`,
+	'⊇': `\supseteq`, '∪': `\cup`, '∩': `\cap`, '⋃': `\bigcup`, '⋂': `\bigcap`, '∅': `\emptyset`,
+	'∀': `\forall`, '∃': `\exists`, '¬': `\neg`, '∧': `\wedge`, '∨': `\vee`, '⊕': `\oplus`,
+	'⊗': `\otimes`, '⊥': `\perp`, '∥': `\parallel`, '∣': `\mid`, '‖': `\|`, '∠': `\angle`,
+	'→': `\to`, '←': `\leftarrow`, '↔': `\leftrightarrow`, '⇒': `\Rightarrow`, '⇐': `\Leftarrow`,
+	'⇔': `\Leftrightarrow`, '↦': `\mapsto`, '↑': `\uparrow`, '↓': `\downarrow`,
+	'…': `\ldots`, '⋯': `\cdots`, '⋮': `\vdots`, '⋱': `\ddots`, '′': `'`, '″': `''`,
+	'⟨': `\langle`, '⟩': `\rangle`, '⌈': `\lceil`, '⌉': `\rceil`, '⌊': `\lfloor`, '⌋': `\rfloor`,
+	'ℝ': `\mathbb{R}`, 'ℕ': `\mathbb{N}`, 'ℤ': `\mathbb{Z}`, 'ℚ': `\mathbb{Q}`, 'ℂ': `\mathbb{C}`,
+	'ℓ': `\ell`, 'ℏ': `\hbar`, '°': `^\circ`,
+	'{': `\{`, '}': `\}`, '%': `\%`, '#': `\#`, '&': `\&`, '_': `\_`, '$': `\$`, '\\': `\backslash`,
+	'⁡': "", '⁢': "", '⁣': "", '⁤': "", // invisible operators
+}
+
+// parseMath parses a MathML <math> element, preferring the TeX annotation
+// over converting the MathML.
+func parseMath(node *html.Node) *types.MathNode {
+	return &types.MathNode{
+		TeX:     mathTeX(node),
+		Display: strings.EqualFold(getAttribute(node, "display"), "block") || getAttribute(node, "mode") == "display",
+	}
+}
+
+// parseMathScript parses a MathJax v2 <script type="math/tex">. Returns nil
+// for other scripts.
+func parseMathScript(node *html.Node) *types.MathNode {
+	kind, params, _ := strings.Cut(strings.ToLower(getAttribute(node, "type")), ";")
+	if strings.TrimSpace(kind) != "math/tex" {
+		return nil
+	}
+	return &types.MathNode{
+		TeX:     strings.TrimSpace(getTextContent(node)),
+		Display: strings.Contains(params, "mode=display"),
+	}
+}
+
+// parseMathWrapper handles the markup math renderers wrap formulas in:
+// KaTeX spans and MathJax v3 containers become a MathNode, and MathJax v2
+// output is dropped in favour of its source script. ok is false for other
+// elements; math is nil when the element should be dropped.
+func parseMathWrapper(node *html.Node) (math *types.MathNode, ok bool) {
+	if strings.ToLower(node.Data) == "mjx-container" {
+		if m := findElement(node, "math"); m != nil {
+			math = parseMath(m)
+			math.Display = math.Display || getAttribute(node, "display") == "true"
+		}
+		return math, true
+	}
+
+	classes := strings.Fields(getAttribute(node, "class"))
+	for _, class := range classes {
+		if _, drop := mathJaxRenderClasses[strings.ToLower(class)]; drop {
+			return nil, true
+		}
+	}
+	for _, class := range classes {
+		if class != "katex" && class != "katex-display" {
+			continue
+		}
+		m := findElement(node, "math")
+		if m == nil {
+			return nil, false
+		}
+		math = parseMath(m)
+		math.Display = math.Display || class == "katex-display"
+		return math, true
+	}
+	return nil, false
+}
+
+// mathTeX returns the TeX source of a <math> element.
+func mathTeX(node *html.Node) string {
+	for _, annotation := range findAllElements(node, "annotation") {
+		if _, ok := texEncodings[strings.ToLower(getAttribute(annotation, "encoding"))]; ok {
+			if tex := strings.TrimSpace(getTextContent(annotation)); tex != "" {
+				return tex
+			}
+		}
+	}
+	return strings.TrimSpace(mathMLToTeX(node))
+}
+
+// mathMLToTeX converts presentation MathML to TeX.
+func mathMLToTeX(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return ""
+	}
+	args := mathArgs(node)
+
+	switch strings.ToLower(node.Data) {
+	case "mi":
+		return texIdentifier(strings.TrimSpace(getTextContent(node)))
+	case "mn":
+		return texText(strings.TrimSpace(getTextContent(node)))
+	case "mo":
+		return texOperator(strings.TrimSpace(getTextContent(node)))
+	case "mtext", "ms":
+		text := getTextContent(node)
+		if strings.TrimSpace(text) == "" {
+			return `\ `
+		}
+		return `\text{` + texEscapeText(text) + `}`
+	case "mspace", "mphantom", "annotation", "annotation-xml", "none", "mprescripts":
+		return ""
+	case "semantics":
+		if len(args) > 0 {
+			return args[0]
+		}
+		return ""
+	case "msup":
+		if len(args) == 2 {
+			return texBase(args[0]) + "^" + texGroup(args[1])
+		}
+	case "msub":
+		if len(args) == 2 {
+			return texBase(args[0]) + "_" + texGroup(args[1])
+		}
+	case "msubsup":
+		if len(args) == 3 {
+			return texBase(args[0]) + "_" + texGroup(args[1]) + "^" + texGroup(args[2])
+		}
+	case "mfrac":
+		if len(args) == 2 {
+			if getAttribute(node, "linethickness") == "0" {
+				return `\binom{` + args[0] + "}{" + args[1] + "}"
+			}
+			return `\frac{` + args[0] + "}{" + args[1] + "}"
+		}
+	case "msqrt":
+		return `\sqrt{` + joinTeX(args) + "}"
+	case "mroot":
+		if len(args) == 2 {
+			return `\sqrt[` + args[1] + "]{" + args[0] + "}"
+		}
+	case "mover":
+		if len(args) == 2 {
+			if accent, ok := texAccents[strings.TrimSpace(getTextContent(mathChildren(node)[1]))]; ok {
+				return accent + "{" + args[0] + "}"
+			}
+			if _, ok := texBigOperators[args[0]]; ok {
+				return args[0] + "^" + texGroup(args[1])
+			}
+			return `\overset{` + args[1] + "}{" + args[0] + "}"
+		}
+	case "munder":
+		if len(args) == 2 {
+			if accent, ok := texUnderAccents[strings.TrimSpace(getTextContent(mathChildren(node)[1]))]; ok {
+				return accent + "{" + args[0] + "}"
+			}
+			if _, ok := texBigOperators[args[0]]; ok {
+				return args[0] + "_" + texGroup(args[1])
+			}
+			return `\underset{` + args[1] + "}{" + args[0] + "}"
+		}
+	case "munderover":
+		if len(args) == 3 {
+			return texBase(args[0]) + "_" + texGroup(args[1]) + "^" + texGroup(args[2])
+		}
+	case "mfenced":
+		open, close, separators := "(", ")", ","
+		if hasAttribute(node, "open") {
+			open = getAttribute(node, "open")
+		}
+		if hasAttribute(node, "close") {
+			close = getAttribute(node, "close")
+		}
+		if hasAttribute(node, "separators") {
+			separators = strings.Join(strings.Fields(getAttribute(node, "separators")), "")
+		}
+		var parts []string
+		seps := []rune(separators)
+		for i, arg := range args {
+			if i > 0 && len(seps) > 0 {
+				parts = append(parts, texOperator(string(seps[min(i-1, len(seps)-1)])))
+			}
+			parts = append(parts, arg)
+		}
+		return `\left` + texDelimiter(open) + " " + joinTeX(parts) + ` \right` + texDelimiter(close)
+	case "mtable":
+		return `\begin{matrix} ` + strings.Join(args, ` \\ `) + ` \end{matrix}`
+	case "mtr":
+		return strings.Join(args, " & ")
+	case "mlabeledtr":
+		if len(args) > 0 {
+			// The first cell is the equation label
+			return strings.Join(args[1:], " & ")
+		}
+		return ""
+	}
+
+	// math, mrow, mstyle, mtd, menclose, mpadded, merror and anything the
+	// cases above could not handle: the content in order
+	return joinTeX(args)
+}
+
+// mathChildren returns the element children of a MathML element.
+func mathChildren(node *html.Node) []*html.Node {
+	var children []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// mathArgs converts the element children of a MathML element.
+func mathArgs(node *html.Node) []string {
+	children := mathChildren(node)
+	args := make([]string, len(children))
+	for i, child := range children {
+		args[i] = mathMLToTeX(child)
+	}
+	return args
+}
+
+// joinTeX concatenates TeX fragments, separating a control word from a
+// following letter so "\alpha" and "x" do not become "\alphax".
+func joinTeX(parts []string) string {
+	var buf strings.Builder
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		first, _ := utf8.DecodeRuneInString(part)
+		if endsWithControlWord(buf.String()) && unicode.IsLetter(first) {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(part)
+	}
+	return buf.String()
+}
+
+// endsWithControlWord reports whether tex ends with a command like "\alpha".
+func endsWithControlWord(tex string) bool {
+	i := len(tex)
+	for i > 0 && isASCIILetter(tex[i-1]) {
+		i--
+	}
+	return i < len(tex) && i > 0 && tex[i-1] == '\\'
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// texGroup braces a script argument unless it is a single character.
+func texGroup(tex string) string {
+	if utf8.RuneCountInString(tex) == 1 {
+		return tex
+	}
+	return "{" + tex + "}"
+}
+
+// texBase braces a compound script base so the script applies to all of it.
+func texBase(tex string) string {
+	if utf8.RuneCountInString(tex) == 1 || (strings.HasPrefix(tex, `\`) && !strings.ContainsAny(tex[1:], `\ ^_{}`)) {
+		return tex
+	}
+	return "{" + tex + "}"
+}
+
+// texIdentifier converts the text of an <mi>: symbols map to commands,
+// known function names become operators and other words are set upright.
+func texIdentifier(text string) string {
+	if utf8.RuneCountInString(text) <= 1 {
+		return texText(text)
+	}
+	if _, ok := texFunctions[text]; ok {
+		return `\` + text
+	}
+	return `\mathrm{` + texText(text) + "}"
+}
+
+// texOperator converts the text of an <mo>.
+func texOperator(text string) string {
+	if _, ok := texFunctions[text]; ok {
+		return `\` + text
+	}
+	return texText(text)
+}
+
+// texText maps the characters of a token to TeX.
+func texText(text string) string {
+	var buf strings.Builder
+	for _, r := range text {
+		if tex, ok := texSymbols[r]; ok {
+			buf.WriteString(tex)
+			continue
+		}
+		if endsWithControlWord(buf.String()) && unicode.IsLetter(r) {
+			buf.WriteByte(' ')
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// texEscapeText escapes the characters that are special in \text{}.
+func texEscapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "$", `\$`,
+		"%", `\%`, "#", `\#`, "&", `\&`, "_", `\_`,
+	).Replace(text)
+}
+
+// texDelimiter converts an <mfenced> open or close character for use after
+// \left or \right, where an empty delimiter is written as ".".
+func texDelimiter(delim string) string {
+	switch delim {
+	case "":
+		return "."
+	case "{", "}":
+		return `\` + delim
+	}
+	return texText(delim)
+}
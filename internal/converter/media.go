@@ -0,0 +1,125 @@
+package converter
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// lazySrcAttributes hold the real image URL on lazy-loaded images, in
+// order of preference (lazysizes, lozad, jQuery Lazy Load, WordPress).
+var lazySrcAttributes = []string{"data-src", "data-lazy-src", "data-original", "data-url"}
+
+// imageSource picks the URL of an <img>. A missing or placeholder src
+// (lazy loading) gives way to the lazy-load attributes, then to the best
+// srcset/data-srcset candidate; the src is kept if there is no alternative.
+func imageSource(node *html.Node) string {
+	src := strings.TrimSpace(getAttribute(node, "src"))
+	if !isPlaceholderSrc(src) {
+		return src
+	}
+
+	for _, attr := range lazySrcAttributes {
+		if lazy := strings.TrimSpace(getAttribute(node, attr)); lazy != "" {
+			return lazy
+		}
+	}
+	if best := bestSrcsetCandidate(getAttribute(node, "srcset"), getAttribute(node, "data-srcset")); best != "" {
+		return best
+	}
+	return src
+}
+
+// isPlaceholderSrc reports whether src is empty or an inline data URI, as
+// lazy loaders use tiny data GIFs until the real image is loaded.
+func isPlaceholderSrc(src string) bool {
+	return src == "" || strings.HasPrefix(strings.ToLower(src), "data:")
+}
+
+// mediaSource returns the URL of a <video> or <audio>: its src (or
+// data-src), otherwise the first <source> child with one.
+func mediaSource(node *html.Node) string {
+	for _, attr := range []string{"src", "data-src"} {
+		if src := strings.TrimSpace(getAttribute(node, attr)); src != "" {
+			return src
+		}
+	}
+	for _, source := range childElements(node, "source") {
+		if src := strings.TrimSpace(getAttribute(source, "src")); src != "" {
+			return src
+		}
+	}
+	return ""
+}
+
+// srcsetCandidate is one image candidate of a srcset attribute.
+type srcsetCandidate struct {
+	url     string
+	width   float64 // from a "480w" descriptor
+	density float64 // from a "2x" descriptor; 1 if no descriptor
+}
+
+// bestSrcsetCandidate returns the highest-resolution candidate of the
+// given srcset values: the widest if any has a width descriptor, otherwise
+// the highest pixel density. Returns "" if there are no candidates.
+func bestSrcsetCandidate(srcsets ...string) string {
+	var best *srcsetCandidate
+	for _, srcset := range srcsets {
+		for _, c := range parseSrcset(srcset) {
+			if best == nil || c.width > best.width ||
+				(c.width == best.width && c.density > best.density) {
+				best = &c
+			}
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.url
+}
+
+// parseSrcset splits a srcset attribute into candidates. URLs end at
+// whitespace, so commas inside a URL (e.g. CDN transforms) are kept.
+func parseSrcset(srcset string) []srcsetCandidate {
+	var candidates []srcsetCandidate
+	rest := srcset
+	for {
+		rest = strings.TrimLeft(rest, " \t\n\r\f,")
+		if rest == "" {
+			return candidates
+		}
+
+		end := strings.IndexAny(rest, " \t\n\r\f")
+		if end == -1 {
+			end = len(rest)
+		}
+		url := rest[:end]
+		rest = rest[end:]
+
+		descriptor := ""
+		if trimmed := strings.TrimRight(url, ","); trimmed != url {
+			// "a.jpg, b.jpg 2x": no descriptor for a.jpg
+			url = trimmed
+		} else if comma := strings.IndexByte(rest, ','); comma != -1 {
+			descriptor, rest = rest[:comma], rest[comma+1:]
+		} else {
+			descriptor, rest = rest, ""
+		}
+
+		c := srcsetCandidate{url: url, density: 1}
+		for _, d := range strings.Fields(descriptor) {
+			value, err := strconv.ParseFloat(d[:len(d)-1], 64)
+			if err != nil {
+				continue
+			}
+			switch d[len(d)-1] {
+			case 'w':
+				c.width = value
+			case 'x':
+				c.density = value
+			}
+		}
+		candidates = append(candidates, c)
+	}
+}
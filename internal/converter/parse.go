@@ -1,63 +1,92 @@
 package converter
 
 import (
+	"context"
 	"strings"
 
 	"github.com/thorstenpfister/semantic-markdown/types"
 	"golang.org/x/net/html"
 )
 
-// Parse converts an HTML node tree to an AST.
-func Parse(node *html.Node, opts *types.ConversionOptions) []types.Node {
-	return parseNode(node, opts, 0)
+// Parse converts an HTML node tree to an AST. Once ctx is canceled, the
+// remaining elements are skipped; callers check ctx.Err() afterwards.
+func Parse(ctx context.Context, node *html.Node, opts *types.ConversionOptions) []types.Node {
+	return collapseWhitespace(parseNode(ctx, node, opts, 0), opts)
 }
 
-func parseNode(node *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
-	var result []types.Node
-
-	for child := node.FirstChild; child != nil; child = child.NextSibling {
-		// Check for override processing
-		if opts.OverrideElementProcessing != nil {
-			if nodes := opts.OverrideElementProcessing(child, opts, indentLevel); nodes != nil {
-				result = append(result, nodes...)
-				continue
-			}
-		}
+func parseNode(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
+	f := &flow{opts: opts}
+	for child := node.FirstChild; child != nil && ctx.Err() == nil; child = child.NextSibling {
+		f.add(child, parseChild(ctx, child, opts, indentLevel))
+	}
+	return f.result()
+}
 
-		switch child.Type {
-		case html.TextNode:
-			// Filter out empty/whitespace-only text nodes
-			if text := strings.TrimSpace(child.Data); text != "" {
-				result = append(result, &types.TextNode{Content: text})
-			}
-		case html.ElementNode:
-			result = append(result, parseElementNode(child, opts, indentLevel)...)
+// parseChild converts a single HTML node (text or element) to AST nodes.
+func parseChild(ctx context.Context, child *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
+	// Check for override processing
+	if opts.OverrideElementProcessing != nil {
+		if nodes := opts.OverrideElementProcessing(child, opts, indentLevel); nodes != nil {
+			return nodes
 		}
 	}
 
-	return result
+	switch child.Type {
+	case html.TextNode:
+		return parseText(child)
+	case html.ElementNode:
+		return parseElementNode(ctx, child, opts, indentLevel)
+	}
+	return nil
 }
 
-func parseElementNode(node *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
+func parseElementNode(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
+	if opts.Footnotes && isFootnoteBacklink(node) {
+		return nil
+	}
+
 	switch strings.ToLower(node.Data) {
 	case "h1", "h2", "h3", "h4", "h5", "h6":
-		return []types.Node{parseHeading(node, opts, indentLevel)}
+		return []types.Node{parseHeading(ctx, node, opts, indentLevel)}
 	case "p":
-		return parseParagraph(node, opts, indentLevel)
+		return parseParagraph(ctx, node, opts, indentLevel)
 	case "a":
-		return []types.Node{parseLink(node, opts, indentLevel)}
+		return []types.Node{parseLink(ctx, node, opts, indentLevel)}
 	case "img":
 		return []types.Node{parseImage(node)}
+	case "picture":
+		return []types.Node{parsePicture(node)}
 	case "video":
 		return []types.Node{parseVideo(node)}
+	case "audio":
+		return []types.Node{parseAudio(node)}
+	case "iframe", "embed":
+		return []types.Node{parseEmbed(node)}
+	case "object":
+		if embed := parseEmbed(node); embed.Src != "" {
+			return []types.Node{embed}
+		}
+		// No data URL: fall back to the object's content
+		return parseNode(ctx, node, opts, indentLevel)
 	case "ul", "ol":
-		return []types.Node{parseList(node, opts, indentLevel)}
+		if opts.Footnotes && isFootnoteList(node) {
+			return parseFootnoteList(ctx, node, opts, indentLevel)
+		}
+		return []types.Node{parseList(ctx, node, opts, indentLevel)}
+	case "dl":
+		return []types.Node{parseDefinitionList(ctx, node, opts, indentLevel)}
 	case "strong", "b":
-		return []types.Node{parseBold(node, opts, indentLevel)}
+		return []types.Node{parseBold(ctx, node, opts, indentLevel)}
 	case "em", "i":
-		return []types.Node{parseItalic(node, opts, indentLevel)}
+		return []types.Node{parseItalic(ctx, node, opts, indentLevel)}
 	case "s", "strike", "del":
-		return []types.Node{parseStrikethrough(node, opts, indentLevel)}
+		return []types.Node{parseStrikethrough(ctx, node, opts, indentLevel)}
+	case "sup":
+		return []types.Node{&types.SuperscriptNode{Content: parseNode(ctx, node, opts, indentLevel)}}
+	case "sub":
+		return []types.Node{&types.SubscriptNode{Content: parseNode(ctx, node, opts, indentLevel)}}
+	case "hr":
+		return []types.Node{&types.ThematicBreakNode{}}
 	case "code":
 		if n := parseCode(node, opts, indentLevel); n != nil {
 			return []types.Node{n}
@@ -66,39 +95,69 @@ func parseElementNode(node *html.Node, opts *types.ConversionOptions, indentLeve
 	case "pre":
 		return []types.Node{parsePreformatted(node, opts, indentLevel)}
 	case "blockquote":
-		return []types.Node{parseBlockquote(node, opts, indentLevel)}
+		return []types.Node{parseBlockquote(ctx, node, opts, indentLevel)}
 	case "table":
-		return []types.Node{parseTable(node, opts, indentLevel)}
+		if code := parseCodeTable(node, opts); code != nil {
+			return []types.Node{code}
+		}
+		return []types.Node{parseTable(ctx, node, opts, indentLevel)}
+	case "button", "clipboard-copy":
+		if isCopyButton(node) {
+			return nil
+		}
+		return parseNode(ctx, node, opts, indentLevel)
 	case "br":
 		return []types.Node{&types.TextNode{Content: "\n"}}
-	case "article", "section", "aside", "nav", "header", "footer", "main", "figure", "figcaption", "details", "summary", "mark", "time":
-		return []types.Node{parseSemanticHTML(node, opts, indentLevel)}
-	case "div", "span":
+	case "figure":
+		return []types.Node{parseFigure(ctx, node, opts, indentLevel)}
+	case "details":
+		return []types.Node{parseDetails(ctx, node, opts, indentLevel)}
+	case "math":
+		return []types.Node{parseMath(node)}
+	case "div", "span", "mjx-container":
+		if math, ok := parseMathWrapper(node); ok {
+			if math == nil {
+				return nil
+			}
+			return []types.Node{math}
+		}
 		// Parse children for generic containers
-		return parseNode(node, opts, indentLevel)
-	case "script", "style", "noscript":
+		return parseNode(ctx, node, opts, indentLevel)
+	case "script":
+		if math := parseMathScript(node); math != nil {
+			return []types.Node{math}
+		}
+		return nil
+	case "style", "noscript":
 		// Ignore these elements
 		return nil
 	default:
+		if _, ok := SemanticTags[strings.ToLower(node.Data)]; ok {
+			return []types.Node{parseSemanticHTML(ctx, node, opts, indentLevel)}
+		}
 		// Handle unrecognized elements by parsing children
 		if opts.ProcessUnhandledElement != nil {
 			if nodes := opts.ProcessUnhandledElement(node, opts, indentLevel); nodes != nil {
 				return nodes
 			}
 		}
-		return parseNode(node, opts, indentLevel)
+		return parseNode(ctx, node, opts, indentLevel)
 	}
 }
 
-func parseHeading(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.HeadingNode {
+func parseHeading(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.HeadingNode {
 	level := int(node.Data[1] - '0') // h1 -> 1, h2 -> 2, etc.
-	content := parseNode(node, opts, indentLevel)
+	content := parseNode(ctx, node, opts, indentLevel)
 	return &types.HeadingNode{
 		Level:   level,
 		Content: content,
 	}
 }
 
-func parseParagraph(node *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
-	return parseNode(node, opts, indentLevel)
+func parseParagraph(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) []types.Node {
+	content := parseNode(ctx, node, opts, indentLevel)
+	if len(content) == 0 {
+		return nil
+	}
+	return []types.Node{&types.ParagraphNode{Content: content}}
 }
@@ -1,6 +1,7 @@
 package converter
 
 import (
+	"context"
 	"strconv"
 	"strings"
 
@@ -30,9 +31,9 @@ func getTextContent(node *html.Node) string {
 	return buf.String()
 }
 
-func parseLink(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.LinkNode {
+func parseLink(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.LinkNode {
 	href := getAttribute(node, "href")
-	content := parseNode(node, opts, indentLevel)
+	content := parseNode(ctx, node, opts, indentLevel)
 	return &types.LinkNode{
 		Href:    href,
 		Content: content,
@@ -40,26 +41,76 @@ func parseLink(node *html.Node, opts *types.ConversionOptions, indentLevel int)
 }
 
 func parseImage(node *html.Node) *types.ImageNode {
-	src := getAttribute(node, "src")
-	alt := getAttribute(node, "alt")
 	return &types.ImageNode{
-		Src: src,
-		Alt: alt,
+		Src: imageSource(node),
+		Alt: getAttribute(node, "alt"),
 	}
 }
 
+// parsePicture parses a <picture> into its <img>, taking the source from
+// the <source> candidates when the <img> has none of its own.
+func parsePicture(node *html.Node) *types.ImageNode {
+	image := &types.ImageNode{}
+	if img := findElement(node, "img"); img != nil {
+		image = parseImage(img)
+		if !isPlaceholderSrc(image.Src) {
+			return image
+		}
+	}
+	for _, source := range childElements(node, "source") {
+		if src := bestSrcsetCandidate(getAttribute(source, "srcset"), getAttribute(source, "data-srcset")); src != "" {
+			image.Src = src
+			break
+		}
+	}
+	return image
+}
+
 func parseVideo(node *html.Node) *types.VideoNode {
-	src := getAttribute(node, "src")
 	poster := getAttribute(node, "poster")
+	if poster == "" {
+		poster = getAttribute(node, "data-poster")
+	}
 	// Check for controls attribute - it can be present without a value
 	controls := hasAttribute(node, "controls")
 	return &types.VideoNode{
-		Src:      src,
+		Src:      mediaSource(node),
 		Poster:   poster,
 		Controls: controls,
 	}
 }
 
+func parseAudio(node *html.Node) *types.AudioNode {
+	return &types.AudioNode{
+		Src:      mediaSource(node),
+		Controls: hasAttribute(node, "controls"),
+	}
+}
+
+// parseEmbed parses <iframe>, <embed> and <object>. The title comes from
+// the title or aria-label attribute (<object> has no src, only data).
+func parseEmbed(node *html.Node) *types.EmbedNode {
+	tag := strings.ToLower(node.Data)
+	src := getAttribute(node, "src")
+	if tag == "object" {
+		src = getAttribute(node, "data")
+	}
+	if src == "" || src == "about:blank" {
+		src = getAttribute(node, "data-src") // lazy-loaded iframes
+	}
+
+	title := getAttribute(node, "title")
+	if title == "" {
+		title = getAttribute(node, "aria-label")
+	}
+
+	return &types.EmbedNode{
+		Src:   strings.TrimSpace(src),
+		Title: title,
+		Tag:   tag,
+	}
+}
+
 // hasAttribute checks if an attribute exists on a node
 func hasAttribute(node *html.Node, key string) bool {
 	for _, attr := range node.Attr {
@@ -70,35 +121,119 @@ func hasAttribute(node *html.Node, key string) bool {
 	return false
 }
 
-func parseList(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.ListNode {
-	ordered := strings.ToLower(node.Data) == "ol"
-	var items []types.ListItemNode
+func parseList(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.ListNode {
+	list := &types.ListNode{
+		Ordered: strings.ToLower(node.Data) == "ol",
+	}
+	if list.Ordered {
+		if start, err := strconv.Atoi(strings.TrimSpace(getAttribute(node, "start"))); err == nil {
+			list.Start = &start
+		}
+		list.Reversed = hasAttribute(node, "reversed")
+	}
 
 	for child := node.FirstChild; child != nil; child = child.NextSibling {
-		if child.Type == html.ElementNode && strings.ToLower(child.Data) == "li" {
-			content := parseNode(child, opts, indentLevel+1)
-			items = append(items, types.ListItemNode{Content: content})
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch strings.ToLower(child.Data) {
+		case "li":
+			item := types.ListItemNode{Content: parseNode(ctx, child, opts, indentLevel+1)}
+			if box := findTaskCheckbox(child); box != nil {
+				item.IsTask = true
+				item.Checked = hasAttribute(box, "checked")
+			}
+			if value, err := strconv.Atoi(strings.TrimSpace(getAttribute(child, "value"))); err == nil {
+				item.Value = &value
+			}
+			list.Items = append(list.Items, item)
+		case "ul", "ol":
+			// Invalid but common: a nested list as a direct child of the list.
+			// Attach it to the preceding item, as browsers render it.
+			nested := parseList(ctx, child, opts, indentLevel+1)
+			if len(list.Items) == 0 {
+				list.Items = append(list.Items, types.ListItemNode{})
+			}
+			last := &list.Items[len(list.Items)-1]
+			last.Content = append(last.Content, nested)
+		}
+	}
+
+	return list
+}
+
+// findTaskCheckbox returns the checkbox <input> that leads a list item
+// (GitHub, Notion and TODO apps put it before the item text), or nil.
+func findTaskCheckbox(li *html.Node) *html.Node {
+	var found *html.Node
+	var walk func(*html.Node) bool // reports whether to keep searching
+	walk = func(n *html.Node) bool {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			switch child.Type {
+			case html.TextNode:
+				if strings.TrimSpace(child.Data) != "" {
+					return false
+				}
+			case html.ElementNode:
+				switch strings.ToLower(child.Data) {
+				case "input":
+					if strings.EqualFold(getAttribute(child, "type"), "checkbox") {
+						found = child
+					}
+					return false
+				case "ul", "ol":
+					// A nested list's checkboxes belong to its own items
+					return false
+				}
+				if !walk(child) {
+					return false
+				}
+			}
 		}
+		return true
 	}
+	walk(li)
+	return found
+}
 
-	return &types.ListNode{
-		Ordered: ordered,
-		Items:   items,
+func parseDefinitionList(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.DefinitionListNode {
+	var items []types.DefinitionItemNode
+
+	var collect func(*html.Node)
+	collect = func(parent *html.Node) {
+		for child := parent.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			switch strings.ToLower(child.Data) {
+			case "dt", "dd":
+				items = append(items, types.DefinitionItemNode{
+					Content: parseNode(ctx, child, opts, indentLevel+1),
+					IsTerm:  strings.ToLower(child.Data) == "dt",
+				})
+			case "div":
+				// HTML allows grouping term/definition pairs in <div> wrappers
+				collect(child)
+			}
+		}
 	}
+	collect(node)
+
+	return &types.DefinitionListNode{Items: items}
 }
 
-func parseBold(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.BoldNode {
-	content := parseNode(node, opts, indentLevel)
+func parseBold(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.BoldNode {
+	content := parseNode(ctx, node, opts, indentLevel)
 	return &types.BoldNode{Content: content}
 }
 
-func parseItalic(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.ItalicNode {
-	content := parseNode(node, opts, indentLevel)
+func parseItalic(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.ItalicNode {
+	content := parseNode(ctx, node, opts, indentLevel)
 	return &types.ItalicNode{Content: content}
 }
 
-func parseStrikethrough(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.StrikethroughNode {
-	content := parseNode(node, opts, indentLevel)
+func parseStrikethrough(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.StrikethroughNode {
+	content := parseNode(ctx, node, opts, indentLevel)
 	return &types.StrikethroughNode{Content: content}
 }
 
@@ -121,25 +256,22 @@ func parsePreformatted(node *html.Node, opts *types.ConversionOptions, indentLev
 	var content string
 	var language string
 
+	var code *html.Node
 	for child := node.FirstChild; child != nil; child = child.NextSibling {
 		if child.Type == html.ElementNode && strings.ToLower(child.Data) == "code" {
-			content = getTextContent(child)
-			// Try to get language from class attribute
-			class := getAttribute(child, "class")
-			if strings.HasPrefix(class, "language-") {
-				language = strings.TrimPrefix(class, "language-")
-			} else if strings.HasPrefix(class, "lang-") {
-				language = strings.TrimPrefix(class, "lang-")
-			}
+			code = child
+			content = codeText(child)
 			break
 		}
 	}
 
 	// If no code element found, just get the text content
 	if content == "" {
-		content = getTextContent(node)
+		content = codeText(node)
 	}
 
+	language = detectCodeLanguage(node, code, content, opts)
+
 	return &types.CodeNode{
 		Content:  content,
 		Language: language,
@@ -147,39 +279,70 @@ func parsePreformatted(node *html.Node, opts *types.ConversionOptions, indentLev
 	}
 }
 
-func parseBlockquote(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.BlockquoteNode {
-	content := parseNode(node, opts, indentLevel)
+func parseBlockquote(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.BlockquoteNode {
+	content := parseNode(ctx, node, opts, indentLevel)
 	return &types.BlockquoteNode{Content: content}
 }
 
-func parseTable(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.TableNode {
+func parseTable(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.TableNode {
 	var rows []types.TableRowNode
-	hasHeader := false
 	var colIDs []string
 
-	// Find tbody, thead, or direct tr children
-	var rowNodes []*html.Node
+	// Find thead, tbody, tfoot or direct tr children, the caption, and column
+	// alignments from <col>. Header rows go first and footer rows last,
+	// wherever they appear in the source.
+	var headRows, bodyRows, footRows []*html.Node
+	var colAligns []string
+	var caption []types.Node
 	for child := node.FirstChild; child != nil; child = child.NextSibling {
 		if child.Type == html.ElementNode {
 			tagName := strings.ToLower(child.Data)
 			switch tagName {
 			case "tr":
-				rowNodes = append(rowNodes, child)
+				bodyRows = append(bodyRows, child)
 			case "thead", "tbody", "tfoot":
 				// Find tr elements within these containers
+				var group []*html.Node
 				for grandchild := child.FirstChild; grandchild != nil; grandchild = grandchild.NextSibling {
 					if grandchild.Type == html.ElementNode && strings.ToLower(grandchild.Data) == "tr" {
-						rowNodes = append(rowNodes, grandchild)
+						group = append(group, grandchild)
 					}
 				}
+				switch tagName {
+				case "thead":
+					headRows = append(headRows, group...)
+				case "tfoot":
+					footRows = append(footRows, group...)
+				default:
+					bodyRows = append(bodyRows, group...)
+				}
+			case "caption":
+				caption = parseNode(ctx, child, opts, indentLevel+1)
+			case "colgroup", "col":
+				colAligns = append(colAligns, parseColAligns(child)...)
 			}
 		}
 	}
-
-	// Parse each row
+	rowNodes := append(append(headRows, bodyRows...), footRows...)
+	hasHeader := len(headRows) > 0
+
+	// Lay out cells on the real column grid: a position covered by a
+	// rowspan/colspan from an earlier cell is skipped, as in the HTML table
+	// model. Only span expansion, column tracking and <col> alignment need
+	// the grid; without it cells are placed one after another.
+	var grid *tableGrid
+	if expandsTableSpans(opts) || opts.EnableTableColumnTracking || len(colAligns) > 0 {
+		grid = &tableGrid{}
+	}
+	var placed []placedCell
 	for rowIdx, rowNode := range rowNodes {
 		var cells []types.TableCellNode
 		colIdx := 0
+		seenData := false
+		rowAlign := parseAlign(rowNode)
+		if rowAlign == "" && rowNode.Parent != nil {
+			rowAlign = parseAlign(rowNode.Parent) // <thead>/<tbody>/<tfoot align>
+		}
 
 		for cellNode := rowNode.FirstChild; cellNode != nil; cellNode = cellNode.NextSibling {
 			if cellNode.Type != html.ElementNode {
@@ -192,63 +355,322 @@ func parseTable(node *html.Node, opts *types.ConversionOptions, indentLevel int)
 			}
 
 			isHeader := cellTag == "th"
-			if rowIdx == 0 && isHeader {
+			if !isHeader {
+				seenData = true
+			}
+
+			// Leading <th> cells of a body row that also has data cells head their row
+			scope := strings.ToLower(strings.TrimSpace(getAttribute(cellNode, "scope")))
+			if scope == "" && isHeader && !seenData && rowIdx >= max(len(headRows), 1) && !allHeaderCells(rowNode) {
+				scope = "row"
+			}
+
+			if rowIdx == 0 && isHeader && scope != "row" {
 				hasHeader = true
 			}
 
 			// Parse cell content
-			cellContent := parseNode(cellNode, opts, indentLevel+1)
+			cellContent := parseNode(ctx, cellNode, opts, indentLevel+1)
 
-			// Get colspan and rowspan
+			// Get colspan and rowspan, clamped to the HTML limits
 			colspan := 1
 			rowspan := 1
 			if colspanStr := getAttribute(cellNode, "colspan"); colspanStr != "" {
 				if val := parseInt(colspanStr); val > 0 {
-					colspan = val
+					colspan = min(val, maxColspan)
 				}
 			}
 			if rowspanStr := getAttribute(cellNode, "rowspan"); rowspanStr != "" {
 				if val := parseInt(rowspanStr); val > 0 {
-					rowspan = val
+					rowspan = min(val, maxRowspan)
 				}
 			}
 
-			// Generate column ID if tracking is enabled
+			// Find the grid column, skipping positions taken by earlier spans
+			if grid != nil {
+				colIdx = grid.nextFree(rowIdx, colIdx)
+				grid.occupy(rowIdx, colIdx, min(rowspan, len(rowNodes)-rowIdx), colspan)
+			}
+
+			// Generate column ID from the grid column if tracking is enabled
 			colID := ""
 			if opts.EnableTableColumnTracking {
 				colID = generateColumnID(colIdx)
-				// Add to colIDs if this is the first row
-				if rowIdx == 0 {
-					colIDs = append(colIDs, colID)
-				}
 			}
 
-			cells = append(cells, types.TableCellNode{
+			// Alignment: the cell itself, then its row group, then its <col>
+			align := parseAlign(cellNode)
+			if align == "" {
+				align = rowAlign
+			}
+			if align == "" && colIdx < len(colAligns) {
+				align = colAligns[colIdx]
+			}
+
+			cell := types.TableCellNode{
 				Content:  cellContent,
 				ColID:    colID,
 				Colspan:  colspan,
 				Rowspan:  rowspan,
 				IsHeader: isHeader,
-			})
+				Align:    align,
+				Scope:    scope,
+				ID:       getAttribute(cellNode, "id"),
+				Headers:  strings.Fields(getAttribute(cellNode, "headers")),
+			}
+			cells = append(cells, cell)
+			placed = append(placed, placedCell{cell: cell, node: cellNode, row: rowIdx, col: colIdx})
 
-			colIdx++
+			colIdx += colspan
 		}
 
 		rows = append(rows, types.TableRowNode{Cells: cells})
 	}
 
+	if expandsTableSpans(opts) {
+		rows = expandTableGrid(ctx, placed, len(rows), opts, indentLevel)
+	}
+
+	if opts.EnableTableColumnTracking {
+		for col := 0; col < grid.width; col++ {
+			colIDs = append(colIDs, generateColumnID(col))
+		}
+	}
+
+	// Split off the <tfoot> rows
+	var footer []types.TableRowNode
+	if len(footRows) > 0 {
+		split := len(rows) - len(footRows)
+		rows, footer = rows[:split:split], rows[split:]
+	}
+
 	return &types.TableNode{
-		Rows:      rows,
-		ColIDs:    colIDs,
-		HasHeader: hasHeader,
+		Rows:       rows,
+		FooterRows: footer,
+		Caption:    caption,
+		ColIDs:     colIDs,
+		HasHeader:  hasHeader,
 	}
 }
 
-func parseSemanticHTML(node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.SemanticHTMLNode {
+// allHeaderCells reports whether every cell of a row is a <th>.
+func allHeaderCells(row *html.Node) bool {
+	for cell := row.FirstChild; cell != nil; cell = cell.NextSibling {
+		if cell.Type == html.ElementNode && strings.ToLower(cell.Data) == "td" {
+			return false
+		}
+	}
+	return true
+}
+
+// maxColspan and maxRowspan are the largest colspan and rowspan values
+// HTML honors; larger values are clamped.
+const (
+	maxColspan = 1000
+	maxRowspan = 65534
+)
+
+// tableGrid tracks which grid positions of a table are taken.
+type tableGrid struct {
+	taken [][]bool
+	width int
+}
+
+// nextFree returns the first free column at or after col in row.
+func (g *tableGrid) nextFree(row, col int) int {
+	for row < len(g.taken) && col < len(g.taken[row]) && g.taken[row][col] {
+		col++
+	}
+	return col
+}
+
+// occupy marks the rows x cols block starting at (row, col) as taken.
+func (g *tableGrid) occupy(row, col, rows, cols int) {
+	for r := row; r < row+rows; r++ {
+		for len(g.taken) <= r {
+			g.taken = append(g.taken, nil)
+		}
+		for len(g.taken[r]) < col+cols {
+			g.taken[r] = append(g.taken[r], false)
+		}
+		for c := col; c < col+cols; c++ {
+			g.taken[r][c] = true
+		}
+	}
+	g.width = max(g.width, col+cols)
+}
+
+// placedCell is a parsed cell with its position on the table grid.
+type placedCell struct {
+	cell     types.TableCellNode
+	node     *html.Node
+	row, col int
+}
+
+// expandsTableSpans reports whether tables are normalized to a full grid.
+func expandsTableSpans(opts *types.ConversionOptions) bool {
+	return opts.TableSpans == types.TableSpanDuplicate || opts.TableSpans == types.TableSpanEmpty
+}
+
+// expandTableGrid builds a full grid of rows from placed cells: every
+// position covered by a span gets its own cell, holding a re-parsed copy of
+// the spanning cell's content (TableSpanDuplicate) or nothing (TableSpanEmpty).
+// Holes left by short rows are filled with empty cells.
+func expandTableGrid(ctx context.Context, placed []placedCell, numRows int, opts *types.ConversionOptions, indentLevel int) []types.TableRowNode {
+	grid := make([][]*types.TableCellNode, numRows)
+	set := func(row, col int, cell *types.TableCellNode) {
+		for len(grid[row]) <= col {
+			grid[row] = append(grid[row], nil)
+		}
+		grid[row][col] = cell
+	}
+
+	for _, p := range placed {
+		origin := p.cell
+		set(p.row, p.col, &origin)
+
+		for r := p.row; r < min(p.row+origin.Rowspan, numRows); r++ {
+			for c := p.col; c < p.col+origin.Colspan; c++ {
+				if r == p.row && c == p.col {
+					continue
+				}
+				filler := &types.TableCellNode{
+					IsHeader: origin.IsHeader,
+					Align:    origin.Align,
+					Spanned:  true,
+				}
+				if opts.EnableTableColumnTracking {
+					filler.ColID = generateColumnID(c)
+				}
+				if opts.TableSpans == types.TableSpanDuplicate {
+					// Parse again so the copy shares no nodes with the original
+					filler.Content = parseNode(ctx, p.node, opts, indentLevel+1)
+				}
+				set(r, c, filler)
+			}
+		}
+	}
+
+	rows := make([]types.TableRowNode, numRows)
+	for r, gridRow := range grid {
+		cells := make([]types.TableCellNode, len(gridRow))
+		for c, cell := range gridRow {
+			if cell != nil {
+				cells[c] = *cell
+			} else if opts.EnableTableColumnTracking {
+				cells[c] = types.TableCellNode{ColID: generateColumnID(c)}
+			}
+		}
+		rows[r].Cells = cells
+	}
+	return rows
+}
+
+// parseColAligns returns the alignment of each column covered by a <col>
+// or the <col> children of a <colgroup>, expanding span attributes.
+func parseColAligns(node *html.Node) []string {
+	var aligns []string
+	appendSpan := func(n *html.Node, align string) {
+		span := parseInt(getAttribute(n, "span"))
+		for i := 0; i < max(span, 1); i++ {
+			aligns = append(aligns, align)
+		}
+	}
+
+	if strings.ToLower(node.Data) == "col" {
+		appendSpan(node, parseAlign(node))
+		return aligns
+	}
+
+	groupAlign := parseAlign(node)
+	hasCols := false
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && strings.ToLower(child.Data) == "col" {
+			hasCols = true
+			align := parseAlign(child)
+			if align == "" {
+				align = groupAlign
+			}
+			appendSpan(child, align)
+		}
+	}
+	if !hasCols {
+		appendSpan(node, groupAlign)
+	}
+	return aligns
+}
+
+// parseAlign returns the horizontal alignment ("left", "center", "right")
+// from a style="text-align: ..." declaration or the legacy align attribute.
+func parseAlign(node *html.Node) string {
+	for _, decl := range strings.Split(getAttribute(node, "style"), ";") {
+		prop, value, ok := strings.Cut(decl, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(prop), "text-align") {
+			if align := normalizeAlign(value); align != "" {
+				return align
+			}
+		}
+	}
+	return normalizeAlign(getAttribute(node, "align"))
+}
+
+func normalizeAlign(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value = strings.TrimSpace(strings.TrimSuffix(value, "!important"))
+	switch value {
+	case "left", "start":
+		return "left"
+	case "center":
+		return "center"
+	case "right", "end":
+		return "right"
+	default:
+		return ""
+	}
+}
+
+// parseFigure splits a <figure> into its content and <figcaption>.
+func parseFigure(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.FigureNode {
+	figure := &types.FigureNode{}
+	content := &flow{opts: opts}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && strings.ToLower(child.Data) == "figcaption" {
+			figure.Caption = append(figure.Caption, parseNode(ctx, child, opts, indentLevel)...)
+			continue
+		}
+		content.add(child, parseChild(ctx, child, opts, indentLevel))
+	}
+	figure.Content = content.result()
+	return figure
+}
+
+// parseDetails splits a <details> into its first <summary> and the body.
+func parseDetails(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.DetailsNode {
+	details := &types.DetailsNode{Open: hasAttribute(node, "open")}
+	content := &flow{opts: opts}
+	hasSummary := false
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if !hasSummary && child.Type == html.ElementNode && strings.ToLower(child.Data) == "summary" {
+			details.Summary = parseNode(ctx, child, opts, indentLevel)
+			hasSummary = true
+			continue
+		}
+		content.add(child, parseChild(ctx, child, opts, indentLevel))
+	}
+	details.Content = content.result()
+	return details
+}
+
+func parseSemanticHTML(ctx context.Context, node *html.Node, opts *types.ConversionOptions, indentLevel int) *types.SemanticHTMLNode {
 	htmlType := strings.ToLower(node.Data)
-	content := parseNode(node, opts, indentLevel)
+	content := parseNode(ctx, node, opts, indentLevel)
+	label := getAttribute(node, "aria-label")
+	if label == "" {
+		label = getAttribute(node, "title")
+	}
 	return &types.SemanticHTMLNode{
 		HTMLType: htmlType,
+		Label:    strings.TrimSpace(label),
 		Content:  content,
 	}
 }
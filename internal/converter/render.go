@@ -2,44 +2,174 @@ package converter
 
 import (
 	"bytes"
+	"context"
+	"io"
 	"strings"
+	"unicode"
 
 	"github.com/thorstenpfister/semantic-markdown/internal/escape"
 	"github.com/thorstenpfister/semantic-markdown/types"
 )
 
 // Render converts an AST to Markdown string.
+// Limits and cancellation are not applied; see RenderContext.
 func Render(nodes []types.Node, opts *types.ConversionOptions) string {
-	escaper := escape.NewEscaper(opts.EscapeMode)
+	var buf strings.Builder
+	_ = renderDocument(context.Background(), &buf, nodes, opts, 0)
+	return buf.String()
+}
 
-	var buf bytes.Buffer
+// RenderContext converts an AST to Markdown string, checking for cancellation
+// between streamed nodes and enforcing opts.MaxOutputBytes.
+func RenderContext(ctx context.Context, nodes []types.Node, opts *types.ConversionOptions) (string, error) {
+	var buf strings.Builder
+	if err := renderDocument(ctx, &buf, nodes, opts, opts.MaxOutputBytes); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderTo streams the Markdown for an AST to w as blocks are finalized.
+// The output is identical to RenderContext. On error, w may have received
+// a partial document.
+func RenderTo(ctx context.Context, w io.Writer, nodes []types.Node, opts *types.ConversionOptions) error {
+	return renderDocument(ctx, w, nodes, opts, opts.MaxOutputBytes)
+}
+
+func renderDocument(ctx context.Context, w io.Writer, nodes []types.Node, opts *types.ConversionOptions, maxOutput int) error {
+	escaper := escape.NewEscaper(opts.EscapeMode)
+	out := &blockWriter{w: w, escaper: escaper, max: maxOutput}
 
 	// Render metadata frontmatter if present (includes URL references when RefifyURLs is enabled)
 	if meta := findMeta(nodes); meta != nil && opts.IncludeMetaData != types.MetaDataNone {
-		buf.WriteString(renderMetadata(meta, opts))
+		if err := out.emit([]byte(renderMetadata(meta, opts))); err != nil {
+			return err
+		}
 	}
 
-	// Render content
-	content := renderNodes(nodes, opts, escaper, 0)
+	// Render content, flushing at block boundaries
+	s := &streamer{ctx: ctx, out: out, opts: opts, esc: escaper}
+	if _, err := s.stream(nodes, true, false); err != nil {
+		return err
+	}
+
+	return out.flush()
+}
+
+// streamer renders nodes to a blockWriter one at a time, checking for
+// cancellation before each node. Semantic wrappers such as <main> or
+// <article> are streamed child by child, so a page wrapped in one is not
+// rendered as a single block.
+type streamer struct {
+	ctx  context.Context
+	out  *blockWriter
+	opts *types.ConversionOptions
+	esc  *escape.Escaper
+}
+
+// stream renders nodes as renderNodes would and reports whether it wrote
+// any content. started tells whether content precedes the nodes in their
+// container; until it does, leading whitespace is dropped as the wrapper's
+// TrimSpace would. separate puts a blank line before the first content, for
+// nodes that form a block of their own.
+func (s *streamer) stream(nodes []types.Node, started, separate bool) (bool, error) {
+	for _, node := range nodes {
+		if err := s.ctx.Err(); err != nil {
+			return started, err
+		}
 
-	// Analyze context and apply escapes where needed (phase 2 of two-phase escaping)
-	content = string(escaper.UnescapeContent([]byte(content)))
+		if n, ok := node.(*types.SemanticHTMLNode); ok {
+			if open, close, ok := semanticWrapper(n, s.opts); ok {
+				wrote, err := s.wrapper(n, open, close, started || separate)
+				if err != nil {
+					return started, err
+				}
+				started = started || wrote
+				continue
+			}
+		}
+
+		rendered := renderNode(node, s.opts, s.esc, 0)
+		if !started {
+			rendered = strings.TrimLeftFunc(rendered, unicode.IsSpace)
+		}
+		if rendered == "" {
+			continue
+		}
+		if (started && isBlockNode(node, s.opts)) || (!started && separate) {
+			s.out.separate()
+		}
+		if err := s.out.write(rendered); err != nil {
+			return started, err
+		}
+		started = true
+	}
+	return started, nil
+}
+
+// wrapper streams a semantic element's content between its open and close
+// text, producing the same output as renderSemanticHTML.
+func (s *streamer) wrapper(n *types.SemanticHTMLNode, open, close string, separate bool) (bool, error) {
+	if open != "" {
+		if separate {
+			s.out.separate()
+		}
+		if err := s.out.write(open); err != nil {
+			return false, err
+		}
+		// The content follows the open text directly
+		separate = false
+	}
 
-	buf.WriteString(content)
+	wrote, err := s.stream(n.Content, false, separate)
+	if err != nil {
+		return false, err
+	}
+	if !wrote && open == "" {
+		// An unwrapped element without content renders nothing
+		return false, nil
+	}
 
-	return strings.TrimRight(buf.String(), "\n\r\t ")
+	if wrote {
+		s.out.trimTrailingSpace()
+	}
+	return true, s.out.write(close)
 }
 
 func renderNodes(nodes []types.Node, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
 	var buf bytes.Buffer
 
 	for _, node := range nodes {
-		buf.WriteString(renderNode(node, opts, esc, indent))
+		out := renderNode(node, opts, esc, indent)
+		if isBlockNode(node, opts) && out != "" {
+			separateBlock(&buf)
+		}
+		buf.WriteString(out)
 	}
 
 	return buf.String()
 }
 
+// separateBlock ends the text in buf with a blank line, if there is any
+// text, so that a following block does not continue the last line.
+func separateBlock(buf *bytes.Buffer) {
+	if buf.Len() > 0 {
+		buf.WriteString(blankLineSuffix(buf.Bytes()))
+	}
+}
+
+// blankLineSuffix returns the newlines that end content with a blank line.
+func blankLineSuffix(content []byte) string {
+	switch {
+	case bytes.HasSuffix(content, []byte("\n\n")):
+		return ""
+	case bytes.HasSuffix(content, []byte("\n")):
+		return "\n"
+	default:
+		return "\n\n"
+	}
+}
+
 func renderNode(node types.Node, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
 	// Check for override renderer
 	if opts.OverrideNodeRenderer != nil {
@@ -58,16 +188,28 @@ func renderNode(node types.Node, opts *types.ConversionOptions, esc *escape.Esca
 		return strings.Repeat("#", n.Level) + " " + strings.TrimSpace(content) + "\n\n"
 
 	case *types.BoldNode:
-		content := renderNodes(n.Content, opts, esc, indent)
-		return "**" + strings.TrimSpace(content) + "**"
+		return renderEmphasis(n.Content, "**", opts, esc, indent)
 
 	case *types.ItalicNode:
-		content := renderNodes(n.Content, opts, esc, indent)
-		return "*" + strings.TrimSpace(content) + "*"
+		return renderEmphasis(n.Content, "*", opts, esc, indent)
 
 	case *types.StrikethroughNode:
-		content := renderNodes(n.Content, opts, esc, indent)
-		return "~~" + strings.TrimSpace(content) + "~~"
+		return renderEmphasis(n.Content, "~~", opts, esc, indent)
+
+	case *types.SuperscriptNode:
+		return renderScript(n.Content, "sup", "^", superscriptRunes, opts, esc, indent)
+
+	case *types.SubscriptNode:
+		return renderScript(n.Content, "sub", "~", subscriptRunes, opts, esc, indent)
+
+	case *types.ThematicBreakNode:
+		return "---\n\n"
+
+	case *types.FootnoteReferenceNode:
+		return "[^" + n.Label + "]"
+
+	case *types.FootnoteNode:
+		return renderFootnote(n, opts, esc, indent)
 
 	case *types.LinkNode:
 		return renderLink(n, opts, esc, indent)
@@ -78,12 +220,36 @@ func renderNode(node types.Node, opts *types.ConversionOptions, esc *escape.Esca
 	case *types.VideoNode:
 		return renderVideo(n)
 
+	case *types.AudioNode:
+		return renderAudio(n)
+
+	case *types.EmbedNode:
+		return renderEmbed(n)
+
+	case *types.FigureNode:
+		return renderFigure(n, opts, esc, indent)
+
+	case *types.DetailsNode:
+		return renderDetails(n, opts, esc, indent)
+
 	case *types.ListNode:
 		return renderList(n, opts, esc, indent)
 
+	case *types.DefinitionListNode:
+		return renderDefinitionList(n, opts, esc, indent)
+
 	case *types.CodeNode:
 		return renderCode(n)
 
+	case *types.MathNode:
+		return renderMath(n)
+
+	case *types.ParagraphNode:
+		if content := strings.TrimSpace(renderNodes(n.Content, opts, esc, indent)); content != "" {
+			return content + "\n\n"
+		}
+		return ""
+
 	case *types.BlockquoteNode:
 		return renderBlockquote(n, opts, esc, indent)
 
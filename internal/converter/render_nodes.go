@@ -2,7 +2,10 @@ package converter
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/thorstenpfister/semantic-markdown/internal/escape"
 	"github.com/thorstenpfister/semantic-markdown/types"
@@ -20,13 +23,77 @@ func isSimpleText(nodes []types.Node) bool {
 
 func renderLink(n *types.LinkNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
 	content := renderNodes(n.Content, opts, esc, indent)
-	content = strings.TrimSpace(content)
 
 	// Use []() for simple text, <a> for complex content
 	if isSimpleText(n.Content) {
-		return fmt.Sprintf("[%s](%s)", content, n.Href)
+		return wrapInline(content, "[", "]("+n.Href+")")
 	}
-	return fmt.Sprintf(`<a href="%s">%s</a>`, n.Href, content)
+	return wrapInline(content, `<a href="`+n.Href+`">`, "</a>")
+}
+
+// renderEmphasis renders bold, italic or strikethrough content between
+// delimiters. Blank content renders as its whitespace only.
+func renderEmphasis(content []types.Node, delim string, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	rendered := renderNodes(content, opts, esc, indent)
+	if strings.TrimSpace(rendered) == "" {
+		return rendered
+	}
+	return wrapInline(rendered, delim, delim)
+}
+
+// wrapInline wraps rendered inline content in open and close, moving its
+// leading and trailing whitespace (including non-breaking spaces) outside:
+// CommonMark does not recognize delimiters next to whitespace, and the
+// space still has to separate the element from the words around it.
+func wrapInline(content, open, close string) string {
+	trimmed := strings.TrimRightFunc(content, unicode.IsSpace)
+	body := strings.TrimLeftFunc(trimmed, unicode.IsSpace)
+	return content[:len(trimmed)-len(body)] + open + body + close + content[len(trimmed):]
+}
+
+// renderScript renders superscript or subscript content in the configured style.
+func renderScript(content []types.Node, tag, delim string, runes map[rune]rune, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	if opts.ScriptStyle == types.ScriptStyleUnicode && isSimpleText(content) {
+		if mapped, ok := mapRunes(plainText(content), runes); ok {
+			return mapped
+		}
+	}
+
+	rendered := renderNodes(content, opts, esc, indent)
+	if strings.TrimSpace(rendered) == "" {
+		return rendered
+	}
+	if opts.ScriptStyle == types.ScriptStyleHTML {
+		return wrapInline(rendered, "<"+tag+">", "</"+tag+">")
+	}
+	return wrapInline(rendered, delim, delim)
+}
+
+// mapRunes maps every rune of s, reporting false if any rune has no mapping.
+func mapRunes(s string, runes map[rune]rune) (string, bool) {
+	if s == "" {
+		return "", false
+	}
+	var buf strings.Builder
+	for _, r := range s {
+		mapped, ok := runes[r]
+		if !ok {
+			return "", false
+		}
+		buf.WriteRune(mapped)
+	}
+	return buf.String(), true
+}
+
+// renderFootnote renders a footnote definition, indenting continuation
+// lines so multi-line content stays part of the footnote.
+func renderFootnote(n *types.FootnoteNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	content := strings.TrimSpace(renderNodes(n.Content, opts, esc, indent))
+
+	if content == "" {
+		return "[^" + n.Label + "]:\n\n"
+	}
+	return "[^" + n.Label + "]: " + indentContinuation(content, "    ") + "\n\n"
 }
 
 func renderImage(n *types.ImageNode) string {
@@ -50,38 +117,426 @@ func renderVideo(n *types.VideoNode) string {
 	return result.String()
 }
 
-func renderList(n *types.ListNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
-	var buf strings.Builder
+func renderAudio(n *types.AudioNode) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("![Audio](%s)\n", n.Src))
+	if n.Controls {
+		result.WriteString(fmt.Sprintf("Controls: %v\n", n.Controls))
+	}
+	return result.String()
+}
 
-	for i, item := range n.Items {
-		content := renderNodes(item.Content, opts, esc, indent)
-		content = strings.TrimSpace(content)
+func renderEmbed(n *types.EmbedNode) string {
+	if n.Src == "" {
+		return ""
+	}
+	title := strings.TrimSpace(n.Title)
+	if title == "" {
+		title = "Embed"
+	}
+	return fmt.Sprintf("[%s](%s)\n", title, n.Src)
+}
+
+// renderFigure renders the figure content followed by the caption as an
+// italic line. With FigureCaptionAlt, the caption becomes the alt text of
+// the figure's image instead if that has none.
+func renderFigure(n *types.FigureNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	caption := strings.Join(strings.Fields(renderNodes(n.Caption, opts, esc, indent)), " ")
+
+	content := n.Content
+	if caption != "" && opts.FigureCaptionStyle == types.FigureCaptionAlt {
+		if withAlt, ok := captionAsAlt(content, plainText(n.Caption)); ok {
+			content, caption = withAlt, ""
+		}
+	}
+
+	result := strings.TrimSpace(renderNodes(content, opts, esc, indent))
+	if caption != "" {
+		if result != "" {
+			result += "\n\n"
+		}
+		result += "*" + caption + "*"
+	}
+	if result == "" {
+		return ""
+	}
+	return result + "\n\n"
+}
+
+// captionAsAlt returns a copy of nodes in which the only image, found at
+// the top level or inside a top-level link, carries alt as its alt text.
+// Reports false if there is not exactly one image or it already has alt text.
+func captionAsAlt(nodes []types.Node, alt string) ([]types.Node, bool) {
+	images := 0
+	types.Inspect(nodes, func(node types.Node) bool {
+		if _, ok := node.(*types.ImageNode); ok {
+			images++
+		}
+		return true
+	})
+	if images != 1 || strings.TrimSpace(alt) == "" {
+		return nil, false
+	}
+
+	result := make([]types.Node, len(nodes))
+	copy(result, nodes)
+	for i, node := range result {
+		switch n := node.(type) {
+		case *types.ImageNode:
+			if strings.TrimSpace(n.Alt) != "" {
+				return nil, false
+			}
+			img := *n
+			img.Alt = strings.TrimSpace(alt)
+			result[i] = &img
+			return result, true
+		case *types.LinkNode:
+			if content, ok := captionAsAlt(n.Content, alt); ok {
+				link := *n
+				link.Content = content
+				result[i] = &link
+				return result, true
+			}
+		}
+	}
+	return nil, false
+}
 
-		// Add indentation
-		indentStr := strings.Repeat("  ", indent)
+// renderDetails renders a disclosure widget in the configured DetailsStyle.
+// A missing summary reads "Details", as browsers display it.
+func renderDetails(n *types.DetailsNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	summary := strings.Join(strings.Fields(renderNodes(n.Summary, opts, esc, indent)), " ")
+	if summary == "" {
+		summary = "Details"
+	}
+	content := strings.TrimSpace(renderNodes(n.Content, opts, esc, indent))
+
+	var result string
+	switch opts.DetailsStyle {
+	case types.DetailsBold:
+		result = "**" + summary + "**"
+	case types.DetailsHeading:
+		result = "### " + summary
+	default:
+		// Blank lines around the body let Markdown renderers parse it
+		tag := "<details>"
+		if n.Open {
+			tag = "<details open>"
+		}
+		result = tag + "\n<summary>" + summary + "</summary>\n\n"
+		if content != "" {
+			result += content + "\n\n"
+		}
+		return result + "</details>\n\n"
+	}
+
+	if content != "" {
+		result += "\n\n" + content
+	}
+	return result + "\n\n"
+}
+
+// renderList renders a list with CommonMark-correct nesting: continuation
+// lines of an item are indented to the width of its marker, and all items
+// are separated by blank lines if any item is loose.
+func renderList(n *types.ListNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	numbers := listNumbers(n)
+	items := make([]string, len(n.Items))
+	loose := false
 
+	for i := range n.Items {
+		content, itemLoose := renderListItem(&n.Items[i], opts, esc, indent+1)
+		loose = loose || itemLoose
+
+		marker := "- "
 		if n.Ordered {
-			buf.WriteString(fmt.Sprintf("%s%d. %s\n", indentStr, i+1, content))
-		} else {
-			buf.WriteString(fmt.Sprintf("%s- %s\n", indentStr, content))
+			marker = strconv.Itoa(numbers[i]) + ". "
+		}
+		if n.Items[i].IsTask {
+			box := "[ ]"
+			if n.Items[i].Checked {
+				box = "[x]"
+			}
+			content = strings.TrimSpace(box + " " + content)
 		}
+		if content == "" {
+			items[i] = strings.TrimSpace(marker)
+			continue
+		}
+		items[i] = marker + indentContinuation(content, strings.Repeat(" ", len(marker)))
+	}
+
+	sep := "\n"
+	if loose {
+		sep = "\n\n"
 	}
+	result := strings.Join(items, sep) + "\n"
 
 	// Add extra newline if at root level
 	if indent == 0 {
-		buf.WriteString("\n")
+		result += "\n"
+	}
+
+	return result
+}
+
+// listNumbers returns the number of each item of an ordered list, following
+// HTML semantics for start, reversed and <li value>.
+// NOTE: CommonMark only honors the first number; later markers are kept
+// literal so reversed or skipping sequences stay readable.
+func listNumbers(n *types.ListNode) []int {
+	next, step := 1, 1
+	if n.Reversed {
+		next, step = len(n.Items), -1
+	}
+	if n.Start != nil {
+		next = *n.Start
+	}
+
+	numbers := make([]int, len(n.Items))
+	for i, item := range n.Items {
+		if item.Value != nil {
+			next = *item.Value
+		}
+		numbers[i] = next
+		next += step
+	}
+
+	// Markers cannot be negative: count up from the first number instead,
+	// clamped to 0
+	if len(numbers) > 0 && slices.Min(numbers) < 0 {
+		first := max(numbers[0], 0)
+		for i := range numbers {
+			numbers[i] = first + i
+		}
+	}
+	return numbers
+}
+
+// renderListItem renders the content of a list item as a sequence of
+// paragraphs and blocks, reporting whether the item is loose (its parts
+// are separated by blank lines). Nested lists directly follow text when
+// CommonMark allows it.
+func renderListItem(item *types.ListItemNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) (string, bool) {
+	var buf, run strings.Builder
+	var prev string // kind of the last emitted part: "inline", "list" or "block"
+	loose := false
+
+	emit := func(text, kind string) {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return
+		}
+		if prev != "" {
+			if prev != "inline" || kind != "list" {
+				buf.WriteString("\n\n")
+				loose = true
+			} else {
+				buf.WriteString("\n")
+			}
+		}
+		buf.WriteString(text)
+		prev = kind
 	}
+	flush := func() {
+		emit(run.String(), "inline")
+		run.Reset()
+	}
+
+	for _, node := range item.Content {
+		out := renderNode(node, opts, esc, indent)
+		if !isBlockNode(node, opts) {
+			run.WriteString(out)
+			continue
+		}
+
+		flush()
+		kind := "block"
+		if list, ok := node.(*types.ListNode); ok && canInterruptParagraph(list) {
+			kind = "list"
+		}
+		emit(out, kind)
+	}
+	flush()
+
+	return buf.String(), loose
+}
+
+// canInterruptParagraph reports whether a list may directly follow a line of
+// text; CommonMark only allows this for bullet lists and lists starting at 1.
+func canInterruptParagraph(n *types.ListNode) bool {
+	return !n.Ordered || len(n.Items) == 0 || listNumbers(n)[0] == 1
+}
+
+// isBlockNode reports whether node renders as a block of its own lines.
+func isBlockNode(node types.Node, opts *types.ConversionOptions) bool {
+	switch n := node.(type) {
+	case *types.CodeNode:
+		return !n.Inline
+	case *types.MathNode:
+		return n.Display
+	case *types.SemanticHTMLNode:
+		return !isInlineSemantic(n, opts)
+	case *types.ListNode, *types.DefinitionListNode, *types.BlockquoteNode, *types.TableNode,
+		*types.HeadingNode, *types.ParagraphNode, *types.ThematicBreakNode, *types.FootnoteNode, *types.FigureNode, *types.DetailsNode:
+		return true
+	default:
+		return false
+	}
+}
+
+// indentContinuation prefixes every line after the first with pad,
+// leaving blank lines empty.
+func indentContinuation(content, pad string) string {
+	lines := strings.Split(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = pad + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
 
+func renderDefinitionList(n *types.DefinitionListNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	var buf strings.Builder
+
+	for i, item := range n.Items {
+		content := strings.TrimSpace(renderNodes(item.Content, opts, esc, indent))
+
+		if item.IsTerm {
+			// Separate each term group from the previous definitions
+			if i > 0 && !n.Items[i-1].IsTerm {
+				buf.WriteString("\n")
+			}
+			if opts.DefinitionListStyle == types.DefinitionListExtra {
+				buf.WriteString(content + "\n")
+			} else {
+				buf.WriteString("**" + content + "**\n")
+			}
+			continue
+		}
+
+		// Definitions: marker on the first line, continuation lines indented
+		// (PHP Markdown Extra needs four spaces)
+		marker, pad := "  ", "  "
+		if opts.DefinitionListStyle == types.DefinitionListExtra {
+			marker, pad = ": ", "    "
+		}
+		buf.WriteString(marker + indentContinuation(content, pad) + "\n")
+	}
+
+	buf.WriteString("\n")
 	return buf.String()
 }
 
+// renderCode renders inline code spans and fenced code blocks whose
+// delimiters cannot be closed by backticks or tildes in the content.
 func renderCode(n *types.CodeNode) string {
 	// NOTE: Content inside code blocks is NOT escaped
+	content := escape.SanitizePlaceholders(n.Content)
 	if n.Inline {
-		return "`" + n.Content + "`"
+		return renderCodeSpan(content)
 	}
 	// Block code
-	return "```" + n.Language + "\n" + n.Content + "\n```\n\n"
+	fence := codeFence(content)
+	return fence + codeInfoString(n.Language) + "\n" + content + "\n" + fence + "\n\n"
+}
+
+// renderMath renders inline math as $tex$ and display math as a $$ block.
+func renderMath(n *types.MathNode) string {
+	// NOTE: TeX is NOT escaped
+	tex := strings.TrimSpace(escape.SanitizePlaceholders(n.TeX))
+	if tex == "" {
+		return ""
+	}
+	if n.Display {
+		// Blank lines would end the block for most renderers
+		var lines []string
+		for _, line := range strings.Split(tex, "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		return "$$\n" + strings.Join(lines, "\n") + "\n$$\n\n"
+	}
+	// A line break would let a blank line end the paragraph inside the span
+	return "$" + strings.Join(strings.Fields(tex), " ") + "$"
+}
+
+// renderCodeSpan delimits inline code with a backtick run whose length
+// occurs nowhere in the content. Content starting or ending with a backtick,
+// or with a space at both ends, is padded with a space on each side, which
+// CommonMark strips again. Line breaks become spaces, as in CommonMark, so
+// a blank line cannot end the paragraph inside the span.
+func renderCodeSpan(content string) string {
+	content = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(content)
+	if content == "" {
+		return ""
+	}
+
+	runs := charRuns(content, '`')
+	n := 1
+	for runs[n] {
+		n++
+	}
+	delimiter := strings.Repeat("`", n)
+
+	if strings.HasPrefix(content, "`") || strings.HasSuffix(content, "`") ||
+		(strings.HasPrefix(content, " ") && strings.HasSuffix(content, " ") && strings.TrimSpace(content) != "") {
+		content = " " + content + " "
+	}
+	return delimiter + content + delimiter
+}
+
+// codeFence returns an opening/closing fence longer than any backtick run
+// in content. Content with long backtick runs but no tilde runs of three or
+// more gets a "~~~" fence instead, which reads better.
+func codeFence(content string) string {
+	backticks := longestRun(charRuns(content, '`'))
+	if backticks < 3 {
+		return "```"
+	}
+	if tildes := longestRun(charRuns(content, '~')); tildes < 3 {
+		return "~~~"
+	}
+	return strings.Repeat("`", backticks+1)
+}
+
+// codeInfoString sanitizes a language for use as an info string: it must
+// be a single word without fence characters, which would either end the
+// info string (backticks) or lengthen the opening fence.
+func codeInfoString(language string) string {
+	fields := strings.Fields(language)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.NewReplacer("`", "", "~", "").Replace(fields[0])
+}
+
+// charRuns returns the set of lengths of consecutive runs of c in s.
+func charRuns(s string, c byte) map[int]bool {
+	runs := make(map[int]bool)
+	run := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] == c {
+			run++
+			continue
+		}
+		if run > 0 {
+			runs[run] = true
+		}
+		run = 0
+	}
+	return runs
+}
+
+// longestRun returns the largest run length in runs.
+func longestRun(runs map[int]bool) int {
+	longest := 0
+	for n := range runs {
+		longest = max(longest, n)
+	}
+	return longest
 }
 
 func renderBlockquote(n *types.BlockquoteNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
@@ -93,41 +548,77 @@ func renderBlockquote(n *types.BlockquoteNode, opts *types.ConversionOptions, es
 	return strings.Join(lines, "\n") + "\n\n"
 }
 
-func renderTable(t *types.TableNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
-	if len(t.Rows) == 0 {
+// pipeCellLines joins the lines of a cell with <br>, since a pipe table row
+// cannot span lines. Blank lines between paragraphs are dropped.
+func pipeCellLines(content string) string {
+	if !strings.Contains(content, "\n") {
+		return content
+	}
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "<br>")
+}
+
+// renderPipeTable renders a GFM pipe table.
+func renderPipeTable(t *types.TableNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	rows := tableRows(t)
+	if len(rows) == 0 {
 		return ""
 	}
 
 	// Calculate max columns (for separator row)
 	maxCols := 0
-	for _, row := range t.Rows {
+	for _, row := range rows {
 		if len(row.Cells) > maxCols {
 			maxCols = len(row.Cells)
 		}
 	}
 
+	if maxCols == 0 {
+		return ""
+	}
+
 	var buf strings.Builder
+	separator := tableSeparator(t, maxCols, opts)
 
-	for rowIdx, row := range t.Rows {
+	// GFM requires a header row; give header-less tables an empty one
+	if !t.HasHeader {
+		buf.WriteString(strings.Repeat("|  ", maxCols) + "|\n")
+		buf.WriteString(separator)
+	}
+
+	for rowIdx, row := range rows {
 		rowStr := ""
 
 		for _, cell := range row.Cells {
 			content := renderNodes(cell.Content, opts, esc, indent+1)
-			content = strings.TrimSpace(content)
+			content = pipeCellLines(strings.TrimSpace(content))
 			// Escape pipes in cell content
 			content = strings.ReplaceAll(content, "|", "\\|")
 
+			// Row headers are bold, as GFM has no header cells outside the first row
+			if cell.Scope == "row" && content != "" {
+				content = "**" + content + "**"
+			}
+
 			// Add column ID comment if tracking enabled
 			if cell.ColID != "" {
 				content += fmt.Sprintf(" <!-- %s -->", cell.ColID)
 			}
 
-			// Add colspan/rowspan comments (but do NOT add empty cells)
-			if cell.Colspan > 1 {
-				content += fmt.Sprintf(" <!-- colspan: %d -->", cell.Colspan)
-			}
-			if cell.Rowspan > 1 {
-				content += fmt.Sprintf(" <!-- rowspan: %d -->", cell.Rowspan)
+			// Add colspan/rowspan comments (but do NOT add empty cells),
+			// unless spans were already expanded into the grid
+			if !expandsTableSpans(opts) {
+				if cell.Colspan > 1 {
+					content += fmt.Sprintf(" <!-- colspan: %d -->", cell.Colspan)
+				}
+				if cell.Rowspan > 1 {
+					content += fmt.Sprintf(" <!-- rowspan: %d -->", cell.Rowspan)
+				}
 			}
 
 			rowStr += "| " + content + " "
@@ -142,11 +633,7 @@ func renderTable(t *types.TableNode, opts *types.ConversionOptions, esc *escape.
 
 		// Add separator row after header row
 		if rowIdx == 0 && t.HasHeader {
-			sep := ""
-			for i := 0; i < maxCols; i++ {
-				sep += "| --- "
-			}
-			buf.WriteString(sep + "|\n")
+			buf.WriteString(separator)
 		}
 	}
 
@@ -154,19 +641,191 @@ func renderTable(t *types.TableNode, opts *types.ConversionOptions, esc *escape.
 	return buf.String()
 }
 
+// tableSeparator builds the header separator row, marking each column's
+// alignment (:---, :---:, ---:).
+func tableSeparator(t *types.TableNode, cols int, opts *types.ConversionOptions) string {
+	var buf strings.Builder
+	for _, align := range columnAligns(t, cols, opts) {
+		switch align {
+		case "left":
+			buf.WriteString("| :--- ")
+		case "center":
+			buf.WriteString("| :---: ")
+		case "right":
+			buf.WriteString("| ---: ")
+		default:
+			buf.WriteString("| --- ")
+		}
+	}
+	return buf.String() + "|\n"
+}
+
+// columnAligns picks one alignment per grid column: the header cell's
+// alignment if set, otherwise the most common alignment among the cells
+// starting in the column.
+func columnAligns(t *types.TableNode, cols int, opts *types.ConversionOptions) []string {
+	aligns := make([]string, cols)
+	rows := tableRows(t)
+	aligned := slices.ContainsFunc(rows, func(row types.TableRowNode) bool {
+		return slices.ContainsFunc(row.Cells, func(cell types.TableCellNode) bool { return cell.Align != "" })
+	})
+	if !aligned {
+		return aligns
+	}
+
+	fromHeader := make([]bool, cols)
+	counts := make([]map[string]int, cols)
+	for rowIdx, columns := range cellColumns(rows, cols, opts) {
+		for i, col := range columns {
+			align := rows[rowIdx].Cells[i].Align
+			if col >= cols || align == "" || fromHeader[col] {
+				continue
+			}
+			if rowIdx == 0 && t.HasHeader {
+				aligns[col], fromHeader[col] = align, true
+				continue
+			}
+			if counts[col] == nil {
+				counts[col] = make(map[string]int)
+			}
+			counts[col][align]++
+			if counts[col][align] > counts[col][aligns[col]] {
+				aligns[col] = align
+			}
+		}
+	}
+	return aligns
+}
+
+// cellColumns returns the grid column each cell starts in, accounting for
+// the rowspan and colspan of earlier cells. Tables with expanded spans
+// already hold one cell per grid column. Columns from cols on are not
+// tracked.
+func cellColumns(rows []types.TableRowNode, cols int, opts *types.ConversionOptions) [][]int {
+	columns := make([][]int, len(rows))
+	grid := &tableGrid{}
+	for r, row := range rows {
+		columns[r] = make([]int, len(row.Cells))
+		col := 0
+		for i, cell := range row.Cells {
+			if expandsTableSpans(opts) {
+				columns[r][i] = i
+				continue
+			}
+			col = grid.nextFree(r, col)
+			columns[r][i] = col
+			if col >= cols {
+				continue
+			}
+			colspan := min(max(cell.Colspan, 1), cols-col)
+			rowspan := min(max(cell.Rowspan, 1), len(rows)-r)
+			grid.occupy(r, col, rowspan, colspan)
+			col += colspan
+		}
+	}
+	return columns
+}
+
+// renderSemanticHTML renders a semantic element according to its policy;
+// see semanticPolicy.
 func renderSemanticHTML(n *types.SemanticHTMLNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
-	content := renderNodes(n.Content, opts, esc, indent)
-	content = strings.TrimSpace(content)
-
-	switch n.HTMLType {
-	case "article":
-		// Article: content directly, no wrapper
-		return content + "\n\n"
-	case "section":
-		// Section: wrapped with horizontal rules
-		return "---\n\n" + content + "\n\n---\n\n"
+	policy := semanticPolicy(n.HTMLType, opts)
+	if policy == types.SemanticDrop {
+		return ""
+	}
+
+	rendered := renderNodes(n.Content, opts, esc, indent)
+	content := strings.TrimSpace(rendered)
+
+	inline := isInlineSemantic(n, opts)
+	if open, close, ok := semanticWrapper(n, opts); ok {
+		if policy == types.SemanticUnwrap && content == "" {
+			return ""
+		}
+		return open + content + close
+	}
+
+	switch policy {
+	case types.SemanticUnwrap:
+		// Only inline elements get here; they stay part of the line
+		return rendered
+	case types.SemanticBlockquote:
+		return renderBlockquote(&types.BlockquoteNode{Content: n.Content}, opts, esc, indent)
+	default:
+		// Custom template
+		result := strings.NewReplacer("{content}", content, "{tag}", n.HTMLType, "{label}", n.Label).Replace(string(policy))
+		if inline {
+			return result
+		}
+		return strings.TrimRight(result, "\n") + "\n\n"
+	}
+}
+
+// semanticWrapper returns the text a block semantic element renders before
+// and after its trimmed content, for the policies that wrap the content as
+// is. Such elements can be streamed child by child.
+func semanticWrapper(n *types.SemanticHTMLNode, opts *types.ConversionOptions) (open, close string, ok bool) {
+	if isInlineSemantic(n, opts) {
+		return "", "", false
+	}
+
+	switch semanticPolicy(n.HTMLType, opts) {
+	case types.SemanticUnwrap:
+		return "", "\n\n", true
+	case types.SemanticRule:
+		return "---\n\n", "\n\n---\n\n", true
+	case types.SemanticHeading:
+		label := n.Label
+		if label == "" {
+			label = strings.ToUpper(n.HTMLType[:1]) + n.HTMLType[1:]
+		}
+		return "## " + label + "\n\n", "\n\n", true
+	case types.SemanticComment:
+		return fmt.Sprintf("<!-- <%s> -->\n", n.HTMLType), fmt.Sprintf("\n<!-- </%s> -->\n\n", n.HTMLType), true
+	}
+	return "", "", false
+}
+
+// isInlineSemantic reports whether a semantic element stays inline: an
+// inline tag (<mark>, <time>) that is unwrapped or rendered through a
+// custom template.
+func isInlineSemantic(n *types.SemanticHTMLNode, opts *types.ConversionOptions) bool {
+	if _, inline := inlineSemanticTags[n.HTMLType]; !inline {
+		return false
+	}
+	switch semanticPolicy(n.HTMLType, opts) {
+	case types.SemanticComment, types.SemanticRule, types.SemanticDrop, types.SemanticHeading, types.SemanticBlockquote:
+		return false
 	default:
-		// All others: HTML comment wrapper
-		return fmt.Sprintf("<!-- <%s> -->\n%s\n<!-- </%s> -->\n\n", n.HTMLType, content, n.HTMLType)
+		return true
+	}
+}
+
+// semanticPolicy returns the policy for a semantic tag: the SemanticElements
+// override if any, otherwise the SemanticPreset's policy.
+func semanticPolicy(tag string, opts *types.ConversionOptions) types.SemanticPolicy {
+	if policy, ok := opts.SemanticElements[tag]; ok {
+		return policy
+	}
+
+	switch opts.SemanticPreset {
+	case types.SemanticPresetMinimal:
+		if tag == "nav" {
+			return types.SemanticDrop
+		}
+		return types.SemanticUnwrap
+	case types.SemanticPresetStructure:
+		if policy, ok := structurePolicies[tag]; ok {
+			return policy
+		}
+		return types.SemanticComment
+	default:
+		switch tag {
+		case "article":
+			return types.SemanticUnwrap
+		case "section":
+			return types.SemanticRule
+		}
+		return types.SemanticComment
 	}
 }
@@ -0,0 +1,401 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/internal/escape"
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
+
+// autoRecordsColumns is the column count above which TableFormatAuto
+// renders a table with a header as records instead of a pipe table.
+const autoRecordsColumns = 8
+
+// renderTable renders a table in the configured TableFormat. Outside of
+// HTML output, the caption is rendered as a bold line above the table.
+func renderTable(t *types.TableNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	if len(tableRows(t)) == 0 {
+		return ""
+	}
+
+	format := opts.TableFormat
+	if format == types.TableFormatAuto {
+		format = chooseTableFormat(t, opts)
+	}
+
+	// Records, CSV and JSON pair values with columns by grid position
+	switch format {
+	case types.TableFormatRecords, types.TableFormatCSV, types.TableFormatJSON:
+		if !expandsTableSpans(opts) && hasTableSpans(t) {
+			t = expandSpans(t)
+		}
+	}
+
+	var table string
+	switch format {
+	case types.TableFormatHTML:
+		return renderHTMLTable(t, opts, esc, indent)
+	case types.TableFormatRecords:
+		table = renderRecordsTable(t, opts, esc, indent)
+	case types.TableFormatCSV:
+		table = renderCSVTable(t)
+	case types.TableFormatJSON:
+		table = renderJSONTable(t)
+	default:
+		table = renderPipeTable(t, opts, esc, indent)
+	}
+
+	caption := renderCaption(t, opts, esc, indent)
+	if caption == "" || table == "" {
+		return table
+	}
+	return "**" + caption + "**\n\n" + table
+}
+
+// renderCaption renders the table caption on a single line.
+func renderCaption(t *types.TableNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	return strings.Join(strings.Fields(renderNodes(t.Caption, opts, esc, indent+1)), " ")
+}
+
+// chooseTableFormat picks the format for TableFormatAuto: HTML when cells
+// hold content a pipe table cannot represent (nested tables, lists, code
+// blocks, line breaks) or span several rows or columns, records for very
+// wide tables with a header, and pipe tables otherwise.
+func chooseTableFormat(t *types.TableNode, opts *types.ConversionOptions) types.TableFormat {
+	if hasTableSpans(t) {
+		return types.TableFormatHTML
+	}
+
+	cols := 0
+	for _, row := range tableRows(t) {
+		cols = max(cols, len(row.Cells))
+		for _, cell := range row.Cells {
+			if hasBlockContent(cell.Content, opts) {
+				return types.TableFormatHTML
+			}
+		}
+	}
+
+	if t.HasHeader && cols > autoRecordsColumns {
+		return types.TableFormatRecords
+	}
+	return types.TableFormatPipe
+}
+
+// hasTableSpans reports whether any cell spans several rows or columns.
+func hasTableSpans(t *types.TableNode) bool {
+	for _, row := range tableRows(t) {
+		for _, cell := range row.Cells {
+			if cell.Colspan > 1 || cell.Rowspan > 1 || cell.Spanned {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expandSpans returns a copy of t with one cell per grid column: every
+// position covered by a rowspan or colspan holds a copy of the spanning
+// cell, marked Spanned. The copies share the original's content nodes.
+func expandSpans(t *types.TableNode) *types.TableNode {
+	rows := tableRows(t)
+	grid := &tableGrid{}
+	cells := make([][]types.TableCellNode, len(rows))
+	set := func(row, col int, cell types.TableCellNode) {
+		for len(cells[row]) <= col {
+			cells[row] = append(cells[row], types.TableCellNode{})
+		}
+		cells[row][col] = cell
+	}
+
+	for r, row := range rows {
+		col := 0
+		for _, cell := range row.Cells {
+			col = grid.nextFree(r, col)
+			colspan := min(max(cell.Colspan, 1), maxColspan)
+			rowspan := min(max(cell.Rowspan, 1), len(rows)-r)
+			grid.occupy(r, col, rowspan, colspan)
+			for rr := r; rr < r+rowspan; rr++ {
+				for c := col; c < col+colspan; c++ {
+					copied := cell
+					copied.Spanned = rr != r || c != col
+					set(rr, c, copied)
+				}
+			}
+			col += colspan
+		}
+	}
+
+	expanded := *t
+	expanded.Rows = make([]types.TableRowNode, len(t.Rows))
+	expanded.FooterRows = make([]types.TableRowNode, len(t.FooterRows))
+	for r := range rows {
+		if r < len(t.Rows) {
+			expanded.Rows[r].Cells = cells[r]
+		} else {
+			expanded.FooterRows[r-len(t.Rows)].Cells = cells[r]
+		}
+	}
+	return &expanded
+}
+
+// hasBlockContent reports whether nodes contain a block or a line break.
+func hasBlockContent(nodes []types.Node, opts *types.ConversionOptions) bool {
+	found := false
+	types.Inspect(nodes, func(node types.Node) bool {
+		if found {
+			return false
+		}
+		if text, ok := node.(*types.TextNode); ok && strings.Contains(text.Content, "\n") {
+			found = true
+		}
+		if isBlockNode(node, opts) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// renderHTMLTable renders a table as HTML, keeping spans and alignment.
+// Cell content is rendered as Markdown; cells holding blocks put it between
+// blank lines, which ends the HTML block so that CommonMark renders it.
+func renderHTMLTable(t *types.TableNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	var buf strings.Builder
+	buf.WriteString("<table>\n")
+	if caption := renderCaption(t, opts, esc, indent); caption != "" {
+		buf.WriteString("<caption>" + caption + "</caption>\n")
+	}
+
+	for i, row := range tableRows(t) {
+		if i == len(t.Rows) {
+			buf.WriteString("<tfoot>\n")
+		}
+		buf.WriteString("<tr>")
+		for _, cell := range row.Cells {
+			if cell.Spanned {
+				// Covered by a colspan/rowspan attribute of another cell
+				continue
+			}
+
+			tag := "td"
+			if cell.IsHeader {
+				tag = "th"
+			}
+
+			var attrs string
+			if cell.Colspan > 1 {
+				attrs += fmt.Sprintf(` colspan="%d"`, cell.Colspan)
+			}
+			if cell.Rowspan > 1 {
+				attrs += fmt.Sprintf(` rowspan="%d"`, cell.Rowspan)
+			}
+			if cell.Align != "" {
+				attrs += fmt.Sprintf(` align="%s"`, cell.Align)
+			}
+			if cell.Scope != "" {
+				attrs += fmt.Sprintf(` scope="%s"`, cell.Scope)
+			}
+
+			content := strings.TrimSpace(renderNodes(cell.Content, opts, esc, indent+1))
+			if cell.ColID != "" {
+				content += fmt.Sprintf(" <!-- %s -->", cell.ColID)
+			}
+
+			if hasBlockContent(cell.Content, opts) {
+				// A blank line ends the HTML block so the Markdown is rendered
+				content = "\n\n" + content + "\n\n"
+			}
+
+			fmt.Fprintf(&buf, "<%s%s>%s</%s>", tag, attrs, content, tag)
+		}
+		buf.WriteString("</tr>\n")
+	}
+	if len(t.FooterRows) > 0 {
+		buf.WriteString("</tfoot>\n")
+	}
+
+	buf.WriteString("</table>\n\n")
+	return buf.String()
+}
+
+// renderRecordsTable renders each body row as a list item of
+// "Header: value" lines, titled by the row header cells if any. Empty
+// cells are omitted. A cell with a headers
+// attribute is keyed by the text of the header cells it references.
+func renderRecordsTable(t *types.TableNode, opts *types.ConversionOptions, esc *escape.Escaper, indent int) string {
+	render := func(cell types.TableCellNode) string {
+		return strings.TrimSpace(renderNodes(cell.Content, opts, esc, indent+1))
+	}
+	keys := tableKeys(t, render)
+
+	// Header cell text by id, for cells with a headers attribute
+	headerText := make(map[string]string)
+	for _, row := range tableRows(t) {
+		for _, cell := range row.Cells {
+			if cell.ID != "" && cell.IsHeader {
+				headerText[cell.ID] = render(cell)
+			}
+		}
+	}
+
+	var items []string
+	for _, row := range tableBody(t) {
+		var lines []string
+		for i, cell := range row.Cells {
+			key := columnKey(keys, i)
+			var names []string
+			for _, id := range cell.Headers {
+				if text := headerText[id]; text != "" {
+					names = append(names, text)
+				}
+			}
+			if len(names) > 0 {
+				key = strings.Join(names, " / ")
+			}
+
+			value := render(cell)
+			switch {
+			case value == "":
+				continue
+			case cell.Scope == "row" && !strings.Contains(value, "\n"):
+				// Row headers title the record
+				lines = append(lines, "**"+value+"**")
+			case strings.Contains(value, "\n"):
+				// Multi-line values (lists, code) go below their key
+				lines = append(lines, key+":\n  "+indentContinuation(value, "  "))
+			default:
+				lines = append(lines, key+": "+value)
+			}
+		}
+		if len(lines) > 0 {
+			items = append(items, "- "+indentContinuation(strings.Join(lines, "\n"), "  "))
+		}
+	}
+
+	if len(items) == 0 {
+		return ""
+	}
+	return strings.Join(items, "\n\n") + "\n\n"
+}
+
+// renderCSVTable renders a table as CSV in a fenced code block.
+// Cells are reduced to plain text.
+func renderCSVTable(t *types.TableNode) string {
+	var buf strings.Builder
+	w := csv.NewWriter(&buf)
+	for _, row := range tableRows(t) {
+		record := make([]string, len(row.Cells))
+		for i, cell := range row.Cells {
+			record[i] = plainText(cell.Content)
+		}
+		_ = w.Write(record) // strings.Builder never fails
+	}
+	w.Flush()
+
+	return renderCode(&types.CodeNode{
+		Content:  strings.TrimSuffix(buf.String(), "\n"),
+		Language: "csv",
+	})
+}
+
+// renderJSONTable renders a table as a JSON array in a fenced code block:
+// one object per body row keyed by header text, or one array per row when
+// the table has no header. Cells are reduced to plain text.
+func renderJSONTable(t *types.TableNode) string {
+	plain := func(cell types.TableCellNode) string { return plainText(cell.Content) }
+	keys := tableKeys(t, plain)
+	var buf strings.Builder
+	buf.WriteString("[")
+	for i, row := range tableBody(t) {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+
+		// Objects are written by hand to keep the column order
+		start, end := "[", "]"
+		if t.HasHeader {
+			start, end = "{", "}"
+		}
+		buf.WriteString(start)
+		for j, cell := range row.Cells {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			if t.HasHeader {
+				buf.WriteString(jsonString(columnKey(keys, j)) + ": ")
+			}
+			buf.WriteString(jsonString(plain(cell)))
+		}
+		buf.WriteString(end)
+	}
+	buf.WriteString("\n]")
+
+	return renderCode(&types.CodeNode{
+		Content:  buf.String(),
+		Language: "json",
+	})
+}
+
+// tableKeys returns a unique key per column for records and JSON output:
+// the header cell text, falling back to "Column N". Repeated header texts
+// get a numeric suffix. Returns nil for tables without a header.
+func tableKeys(t *types.TableNode, text func(types.TableCellNode) string) []string {
+	if !t.HasHeader {
+		return nil
+	}
+
+	header := t.Rows[0].Cells
+	keys := make([]string, len(header))
+	seen := make(map[string]int)
+	for i, cell := range header {
+		key := text(cell)
+		if key == "" {
+			key = columnKey(nil, i)
+		}
+		if seen[key]++; seen[key] > 1 {
+			key += " " + strconv.Itoa(seen[key])
+		}
+		keys[i] = key
+	}
+	return keys
+}
+
+// columnKey returns the key of column i, naming columns without a header
+// cell "Column N".
+func columnKey(keys []string, i int) string {
+	if i < len(keys) {
+		return keys[i]
+	}
+	return "Column " + strconv.Itoa(i+1)
+}
+
+// tableRows returns all rows of a table, footer rows last.
+func tableRows(t *types.TableNode) []types.TableRowNode {
+	return slices.Concat(t.Rows, t.FooterRows)
+}
+
+// tableBody returns the rows below the header row, footer rows included.
+func tableBody(t *types.TableNode) []types.TableRowNode {
+	rows := tableRows(t)
+	if t.HasHeader && len(rows) > 0 {
+		return rows[1:]
+	}
+	return rows
+}
+
+// jsonString encodes s as a JSON string without escaping HTML characters.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // strings always encode
+	return strings.TrimSuffix(buf.String(), "\n")
+}
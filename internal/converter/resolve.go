@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+	"golang.org/x/net/html"
+)
+
+// FindBaseURL determines the base URL for resolving relative URLs.
+// A <base href> in the document takes precedence; it is itself resolved
+// against the website domain when relative. Returns nil if neither is usable.
+func FindBaseURL(doc *html.Node, websiteDomain string) *url.URL {
+	domain := parseDomain(websiteDomain)
+
+	if base := findElement(doc, "base"); base != nil {
+		if href := strings.TrimSpace(getAttribute(base, "href")); href != "" {
+			if ref, err := url.Parse(href); err == nil {
+				if domain != nil {
+					ref = domain.ResolveReference(ref)
+				}
+				if ref.IsAbs() {
+					return ref
+				}
+			}
+		}
+	}
+
+	return domain
+}
+
+// parseDomain parses the website domain, assuming https when no scheme is given.
+func parseDomain(domain string) *url.URL {
+	domain = strings.TrimSpace(domain)
+	if domain == "" {
+		return nil
+	}
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	u, err := url.Parse(domain)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	return u
+}
+
+// ResolveURLs rewrites relative link, image and media URLs to absolute URLs.
+// NOTE: Fragment-only links (#anchor) and data URIs are preserved as-is.
+func ResolveURLs(nodes []types.Node, base *url.URL) {
+	if base == nil {
+		return
+	}
+	types.Inspect(nodes, func(node types.Node) bool {
+		switch n := node.(type) {
+		case *types.LinkNode:
+			n.Href = resolveURL(n.Href, base)
+		case *types.ImageNode:
+			n.Src = resolveURL(n.Src, base)
+		case *types.VideoNode:
+			n.Src = resolveURL(n.Src, base)
+			if n.Poster != "" {
+				n.Poster = resolveURL(n.Poster, base)
+			}
+		case *types.AudioNode:
+			n.Src = resolveURL(n.Src, base)
+		case *types.EmbedNode:
+			n.Src = resolveURL(n.Src, base)
+		}
+		return true
+	})
+}
+
+func resolveURL(raw string, base *url.URL) string {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return raw
+	}
+
+	ref, err := url.Parse(trimmed)
+	if err != nil || ref.IsAbs() {
+		// Absolute URLs (including data:, mailto:, javascript:) are left untouched
+		return raw
+	}
+
+	return base.ResolveReference(ref).String()
+}
@@ -0,0 +1,124 @@
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode"
+
+	"github.com/thorstenpfister/semantic-markdown/internal/escape"
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
+
+// blockWriter applies phase 2 of the two-phase escaping per block and streams
+// the result to an underlying writer.
+//
+// Rendered content is buffered until it ends in a blank line. Every escape
+// pattern only inspects the current line (setext headers also the previous
+// line), so unescaping each blank-line-delimited block on its own yields the
+// same result as unescaping the whole document at once.
+//
+// Trailing whitespace is held back until more content follows, so the
+// document's final trailing whitespace is trimmed as in Render.
+type blockWriter struct {
+	w       io.Writer
+	escaper *escape.Escaper
+	max     int // maximum output bytes, 0 for unlimited
+
+	pending      bytes.Buffer // phase 1 output not yet unescaped
+	placeholders int          // placeholder bytes in pending
+	held         []byte       // trailing whitespace not yet written
+	written      int
+}
+
+// write buffers rendered content and flushes it once it ends in a blank line.
+func (b *blockWriter) write(rendered string) error {
+	b.pending.WriteString(rendered)
+	b.placeholders += bytes.Count([]byte(rendered), []byte{escape.PlaceholderByte})
+
+	// Each placeholder is dropped or becomes a single backslash, so the
+	// output is at least the pending size without placeholders
+	if b.max > 0 && b.written+b.pending.Len()-b.placeholders > b.max {
+		return b.limitError()
+	}
+
+	if bytes.HasSuffix(b.pending.Bytes(), []byte("\n\n")) {
+		return b.flush()
+	}
+	return nil
+}
+
+// separate makes sure a blank line follows the content so far, so that
+// the next block starts on a line of its own.
+func (b *blockWriter) separate() {
+	switch {
+	case b.pending.Len() > 0:
+		b.pending.WriteString(blankLineSuffix(b.pending.Bytes()))
+	case b.written > 0:
+		b.held = append(b.held, blankLineSuffix(b.held)...)
+	}
+}
+
+// trimTrailingSpace drops the whitespace after the last content, as
+// strings.TrimSpace does for content rendered into a string.
+func (b *blockWriter) trimTrailingSpace() {
+	trimmed := bytes.TrimRightFunc(b.pending.Bytes(), unicode.IsSpace)
+	b.pending.Truncate(len(trimmed))
+	if len(trimmed) == 0 {
+		b.held = b.held[:0]
+	}
+}
+
+// flush unescapes and writes all pending content.
+func (b *blockWriter) flush() error {
+	if b.pending.Len() == 0 {
+		return nil
+	}
+	content := b.escaper.UnescapeContent(b.pending.Bytes())
+	b.pending.Reset()
+	b.placeholders = 0
+	return b.emit(content)
+}
+
+// emit writes final output, holding back trailing whitespace.
+func (b *blockWriter) emit(content []byte) error {
+	trimmed := bytes.TrimRight(content, "\n\r\t ")
+	if len(trimmed) == 0 {
+		b.held = append(b.held, content...)
+		return nil
+	}
+
+	if b.max > 0 && b.written+len(b.held)+len(trimmed) > b.max {
+		return b.limitError()
+	}
+
+	if len(b.held) > 0 {
+		if _, err := b.w.Write(b.held); err != nil {
+			return err
+		}
+		b.written += len(b.held)
+	}
+	if _, err := b.w.Write(trimmed); err != nil {
+		return err
+	}
+	b.written += len(trimmed)
+
+	b.held = append(b.held[:0], content[len(trimmed):]...)
+	return nil
+}
+
+func (b *blockWriter) limitError() error {
+	return fmt.Errorf("%w (limit %d bytes)", types.ErrOutputTooLarge, b.max)
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -9,7 +9,7 @@ import (
 
 // RefifyURLs converts long URLs to reference format for token reduction.
 // Returns a map of reference IDs to original URL prefixes.
-// NOTE: Relative URLs are preserved as-is (not resolved to absolute).
+// NOTE: Relative URLs are skipped; enable ResolveURLs to make them absolute first.
 // NOTE: Data URIs are preserved at full length.
 func RefifyURLs(nodes []types.Node) map[string]string {
 	prefixesToRefs := make(map[string]string)
@@ -24,11 +24,10 @@ func RefifyURLs(nodes []types.Node) map[string]string {
 }
 
 func refifyNodes(nodes []types.Node, refs map[string]string) {
-	for _, node := range nodes {
+	types.Inspect(nodes, func(node types.Node) bool {
 		switch n := node.(type) {
 		case *types.LinkNode:
 			n.Href = processURL(n.Href, refs)
-			refifyNodes(n.Content, refs)
 		case *types.ImageNode:
 			n.Src = processURL(n.Src, refs)
 		case *types.VideoNode:
@@ -36,30 +35,13 @@ func refifyNodes(nodes []types.Node, refs map[string]string) {
 			if n.Poster != "" {
 				n.Poster = processURL(n.Poster, refs)
 			}
-		case *types.ListNode:
-			for i := range n.Items {
-				refifyNodes(n.Items[i].Content, refs)
-			}
-		case *types.TableNode:
-			for i := range n.Rows {
-				for j := range n.Rows[i].Cells {
-					refifyNodes(n.Rows[i].Cells[j].Content, refs)
-				}
-			}
-		case *types.BlockquoteNode:
-			refifyNodes(n.Content, refs)
-		case *types.SemanticHTMLNode:
-			refifyNodes(n.Content, refs)
-		case *types.BoldNode:
-			refifyNodes(n.Content, refs)
-		case *types.ItalicNode:
-			refifyNodes(n.Content, refs)
-		case *types.StrikethroughNode:
-			refifyNodes(n.Content, refs)
-		case *types.HeadingNode:
-			refifyNodes(n.Content, refs)
+		case *types.AudioNode:
+			n.Src = processURL(n.Src, refs)
+		case *types.EmbedNode:
+			n.Src = processURL(n.Src, refs)
 		}
-	}
+		return true
+	})
 }
 
 func processURL(url string, refs map[string]string) string {
@@ -0,0 +1,316 @@
+package converter
+
+import (
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+	"golang.org/x/net/html"
+)
+
+// htmlSpaces are the characters HTML collapses; U+00A0 (&nbsp;) is not one.
+const htmlSpaces = " \t\n\r\f"
+
+// parseText converts an HTML text node according to the white-space
+// property in effect for it. Collapsible whitespace is reduced to single
+// spaces here; collapseWhitespace removes the spaces that CSS would drop
+// at inline boundaries and line ends once the whole tree is parsed.
+func parseText(node *html.Node) []types.Node {
+	if node.Data == "" {
+		return nil
+	}
+
+	switch whiteSpaceMode(node.Parent) {
+	case "pre", "pre-wrap", "break-spaces":
+		return []types.Node{&types.TextNode{Content: node.Data, Preformatted: true}}
+	case "pre-line":
+		// Line breaks are kept, other whitespace collapses
+		var nodes []types.Node
+		for i, line := range strings.Split(strings.ReplaceAll(node.Data, "\r\n", "\n"), "\n") {
+			if i > 0 {
+				nodes = append(nodes, &types.TextNode{Content: "\n"})
+			}
+			if text := collapseSpaces(line); text != "" {
+				nodes = append(nodes, &types.TextNode{Content: text})
+			}
+		}
+		return nodes
+	}
+	return []types.Node{&types.TextNode{Content: collapseSpaces(node.Data)}}
+}
+
+// collapseSpaces replaces every run of HTML whitespace with a single space.
+func collapseSpaces(s string) string {
+	if !strings.ContainsAny(s, htmlSpaces) {
+		return s
+	}
+	var buf strings.Builder
+	space := false
+	for _, r := range s {
+		if strings.ContainsRune(htmlSpaces, r) {
+			space = true
+			continue
+		}
+		if space {
+			buf.WriteByte(' ')
+			space = false
+		}
+		buf.WriteRune(r)
+	}
+	if space {
+		buf.WriteByte(' ')
+	}
+	return buf.String()
+}
+
+// whiteSpaceMode returns the white-space value from the nearest
+// style="white-space: ..." declaration on node or its ancestors, or
+// "normal" if there is none.
+func whiteSpaceMode(node *html.Node) string {
+	for n := node; n != nil; n = n.Parent {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		for _, decl := range strings.Split(getAttribute(n, "style"), ";") {
+			prop, value, ok := strings.Cut(decl, ":")
+			if ok && strings.EqualFold(strings.TrimSpace(prop), "white-space") {
+				value = strings.ToLower(strings.TrimSpace(value))
+				return strings.TrimSpace(strings.TrimSuffix(value, "!important"))
+			}
+		}
+	}
+	return "normal"
+}
+
+// collapseWhitespace finishes CSS white-space: normal processing across the
+// parsed tree: a space following another space in the same line is dropped,
+// even across inline elements, and spaces at the start and end of blocks
+// and around line breaks are removed. Text nodes left empty are dropped.
+func collapseWhitespace(nodes []types.Node, opts *types.ConversionOptions) []types.Node {
+	collapseBlock(nodes, opts)
+	return types.Rewrite(nodes, func(node types.Node) []types.Node {
+		if text, ok := node.(*types.TextNode); ok && text.Content == "" {
+			return nil
+		}
+		return []types.Node{node}
+	})
+}
+
+// inlineRun tracks the collapsible whitespace of the current line.
+type inlineRun struct {
+	opts  *types.ConversionOptions
+	space bool            // the line is empty or ends in a collapsible space
+	last  *types.TextNode // text holding that trailing space, if any
+}
+
+// collapseBlock collapses the whitespace of the lines formed by nodes.
+func collapseBlock(nodes []types.Node, opts *types.ConversionOptions) {
+	run := &inlineRun{opts: opts, space: true}
+	run.visit(nodes)
+	run.lineBreak()
+}
+
+func (r *inlineRun) visit(nodes []types.Node) {
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *types.TextNode:
+			r.text(n)
+		case *types.BoldNode:
+			r.visit(n.Content)
+		case *types.ItalicNode:
+			r.visit(n.Content)
+		case *types.StrikethroughNode:
+			r.visit(n.Content)
+		case *types.SuperscriptNode:
+			r.visit(n.Content)
+		case *types.SubscriptNode:
+			r.visit(n.Content)
+		case *types.LinkNode:
+			r.visit(n.Content)
+		case *types.SemanticHTMLNode:
+			// Unwrapped and templated <mark> and <time> stay inline
+			if isInlineSemantic(n, r.opts) {
+				r.visit(n.Content)
+				continue
+			}
+			r.block(node)
+		case *types.ImageNode, *types.VideoNode, *types.AudioNode, *types.EmbedNode:
+			// Rendered with a line break after them
+			r.space, r.last = true, nil
+		default:
+			if isBlockNode(node, r.opts) {
+				r.block(node)
+				continue
+			}
+			// Images, inline code and math, footnote references, ...
+			r.space, r.last = false, nil
+		}
+	}
+}
+
+// text collapses a text node against the run so far.
+func (r *inlineRun) text(n *types.TextNode) {
+	switch {
+	case n.Preformatted:
+		r.space, r.last = false, nil
+		return
+	case n.Content != "" && strings.Trim(n.Content, "\n") == "":
+		// Line break from <br> or a pre-line newline
+		r.lineBreak()
+		return
+	}
+
+	if r.space {
+		n.Content = strings.TrimLeft(n.Content, " ")
+	}
+	if n.Content == "" {
+		return
+	}
+	r.space = strings.HasSuffix(n.Content, " ")
+	r.last = nil
+	if r.space {
+		r.last = n
+	}
+}
+
+// lineBreak ends the current line, dropping its trailing space.
+func (r *inlineRun) lineBreak() {
+	if r.last != nil {
+		r.last.Content = strings.TrimRight(r.last.Content, " ")
+	}
+	r.space, r.last = true, nil
+}
+
+// block ends the current line and collapses the blocks inside node on
+// their own.
+func (r *inlineRun) block(node types.Node) {
+	r.lineBreak()
+	for _, content := range blockContents(node) {
+		collapseBlock(content, r.opts)
+	}
+}
+
+// blockContents returns the content slices of a block node that form
+// blocks of their own.
+func blockContents(node types.Node) [][]types.Node {
+	switch n := node.(type) {
+	case *types.HeadingNode:
+		return [][]types.Node{n.Content}
+	case *types.ParagraphNode:
+		return [][]types.Node{n.Content}
+	case *types.BlockquoteNode:
+		return [][]types.Node{n.Content}
+	case *types.SemanticHTMLNode:
+		return [][]types.Node{n.Content}
+	case *types.FootnoteNode:
+		return [][]types.Node{n.Content}
+	case *types.FigureNode:
+		return [][]types.Node{n.Content, n.Caption}
+	case *types.DetailsNode:
+		return [][]types.Node{n.Summary, n.Content}
+	case *types.ListNode:
+		contents := make([][]types.Node, len(n.Items))
+		for i := range n.Items {
+			contents[i] = n.Items[i].Content
+		}
+		return contents
+	case *types.DefinitionListNode:
+		contents := make([][]types.Node, len(n.Items))
+		for i := range n.Items {
+			contents[i] = n.Items[i].Content
+		}
+		return contents
+	case *types.TableNode:
+		contents := [][]types.Node{n.Caption}
+		for _, row := range tableRows(n) {
+			for _, cell := range row.Cells {
+				contents = append(contents, cell.Content)
+			}
+		}
+		return contents
+	default:
+		return nil
+	}
+}
+
+// blockTags are elements laid out as CSS blocks that have no block node of
+// their own: their content starts and ends a line.
+var blockTags = map[string]struct{}{
+	"div": {}, "address": {}, "center": {}, "dialog": {}, "fieldset": {}, "form": {},
+	"hgroup": {}, "legend": {}, "search": {},
+}
+
+// flow collects the nodes parsed from sibling HTML nodes, putting the
+// content of block elements on lines of their own as CSS does.
+type flow struct {
+	opts      *types.ConversionOptions
+	nodes     []types.Node
+	breakNext bool // a block element ended the line
+}
+
+// add appends the nodes parsed from child.
+func (f *flow) add(child *html.Node, nodes []types.Node) {
+	if len(nodes) == 0 {
+		return
+	}
+	_, block := blockTags[strings.ToLower(child.Data)]
+	block = block && child.Type == html.ElementNode
+	if (block || f.breakNext) && !isBlankText(nodes...) {
+		f.lineBreak(nodes[0])
+		f.breakNext = false
+	}
+	f.nodes = append(f.nodes, nodes...)
+	if block {
+		f.breakNext = true
+	}
+}
+
+// lineBreak ends the current line before next, unless the content so far
+// or next already starts a new line.
+func (f *flow) lineBreak(next types.Node) {
+	if isBlockNode(next, f.opts) {
+		return
+	}
+	for i := len(f.nodes) - 1; i >= 0; i-- {
+		last := f.nodes[i]
+		if isBlankText(last) {
+			continue
+		}
+		if text, ok := last.(*types.TextNode); ok && strings.HasSuffix(text.Content, "\n") {
+			return
+		}
+		if !isBlockNode(last, f.opts) {
+			f.nodes = append(f.nodes, &types.TextNode{Content: "\n"})
+		}
+		return
+	}
+}
+
+// result returns the collected nodes. A lone paragraph is unwrapped, as
+// there is nothing to separate it from.
+func (f *flow) result() []types.Node {
+	var paragraph *types.ParagraphNode
+	for _, node := range f.nodes {
+		if p, ok := node.(*types.ParagraphNode); ok && paragraph == nil {
+			paragraph = p
+			continue
+		}
+		if !isBlankText(node) {
+			return f.nodes
+		}
+	}
+	if paragraph == nil {
+		return f.nodes
+	}
+	return paragraph.Content
+}
+
+// isBlankText reports whether nodes are only collapsible spaces.
+func isBlankText(nodes ...types.Node) bool {
+	for _, node := range nodes {
+		text, ok := node.(*types.TextNode)
+		if !ok || text.Preformatted || strings.TrimLeft(text.Content, " ") != "" {
+			return false
+		}
+	}
+	return true
+}
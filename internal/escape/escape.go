@@ -1,7 +1,12 @@
 // Package escape provides smart markdown character escaping.
 package escape
 
-import "github.com/thorstenpfister/semantic-markdown/types"
+import (
+	"slices"
+	"strings"
+
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
 
 const PlaceholderByte byte = 0x1A // ASCII SUB character
 
@@ -18,6 +23,7 @@ var EscapedChars = map[rune]bool{
 type Escaper struct {
 	mode     types.EscapeMode
 	patterns []PatternFunc
+	builders []PatternBuilder
 }
 
 // PatternFunc checks if a character at index needs escaping.
@@ -25,6 +31,11 @@ type Escaper struct {
 // NOTE: First matching pattern wins - order matters!
 type PatternFunc func(chars []byte, index int) int
 
+// PatternBuilder prepares a PatternFunc for the content being unescaped,
+// for patterns that look beyond the current line. Built patterns are
+// checked after the fixed ones.
+type PatternBuilder func(content []byte) PatternFunc
+
 // NewEscaper creates a new escaper with CommonMark patterns.
 func NewEscaper(mode types.EscapeMode) *Escaper {
 	e := &Escaper{mode: mode}
@@ -44,6 +55,9 @@ func NewEscaper(mode types.EscapeMode) *Escaper {
 			IsInlineCode,
 			IsBackslash,
 		}
+		e.builders = []PatternBuilder{
+			MathDelimiter,
+		}
 	}
 
 	return e
@@ -58,8 +72,8 @@ func (e *Escaper) EscapeContent(content []byte) []byte {
 	result := make([]byte, 0, len(content)*2)
 
 	for i := 0; i < len(content); i++ {
-		// Replace null bytes for security
-		if content[i] == 0x00 {
+		// Replace null bytes for security, and literal placeholder bytes
+		if content[i] == 0x00 || content[i] == PlaceholderByte {
 			result = append(result, []byte(string('\ufffd'))...)
 			continue
 		}
@@ -75,12 +89,27 @@ func (e *Escaper) EscapeContent(content []byte) []byte {
 	return result
 }
 
+// SanitizePlaceholders replaces literal placeholder bytes in content that is
+// not passed through EscapeContent (e.g. code), so UnescapeContent does not
+// mistake them for placeholders.
+func SanitizePlaceholders(content string) string {
+	return strings.ReplaceAll(content, string(PlaceholderByte), "\ufffd")
+}
+
 // UnescapeContent analyzes context and applies escapes where needed.
 func (e *Escaper) UnescapeContent(content []byte) []byte {
 	if e.mode == types.EscapeModeDisabled {
 		return content
 	}
 
+	patterns := e.patterns
+	if len(e.builders) > 0 {
+		patterns = slices.Clone(e.patterns)
+		for _, build := range e.builders {
+			patterns = append(patterns, build(content))
+		}
+	}
+
 	// Determine which placeholders need actual escaping
 	actions := make([]bool, len(content)) // true = escape
 
@@ -93,7 +122,7 @@ func (e *Escaper) UnescapeContent(content []byte) []byte {
 		}
 
 		// Check all patterns
-		for _, pattern := range e.patterns {
+		for _, pattern := range patterns {
 			if skip := pattern(content, i+1); skip != -1 {
 				actions[i] = true
 				i += skip - 1
@@ -1,6 +1,9 @@
 package escape
 
-import "unicode"
+import (
+	"bytes"
+	"unicode"
+)
 
 // IsItalicOrBold detects emphasis markers that need escaping.
 func IsItalicOrBold(chars []byte, index int) int {
@@ -290,6 +293,59 @@ func IsInlineCode(chars []byte, index int) int {
 	return count
 }
 
+// MathDelimiter returns a pattern detecting a $ in content that would open
+// inline math: it is followed by a non-space and a later $ in the same
+// paragraph closes it. Lone dollars such as "$5 and $10" are left alone.
+// The closers are found in one pass up front, so each $ is decided in
+// constant time.
+func MathDelimiter(content []byte) PatternFunc {
+	if bytes.IndexByte(content, '$') < 0 {
+		return func([]byte, int) int { return -1 }
+	}
+
+	// nextCloser[i] is the first $ at or after i, before the next blank
+	// line, that can close math: preceded by a non-space and not followed
+	// by a digit. -1 if there is none.
+	nextCloser := make([]int, len(content)+1)
+	nextCloser[len(content)] = -1
+	next := -1
+	for i := len(content) - 1; i >= 0; i-- {
+		switch content[i] {
+		case '\n':
+			if getNextRune(content, i) == '\n' {
+				next = -1 // Blank line ends the paragraph
+			}
+		case '$':
+			after := getNextRune(content, i)
+			if !unicode.IsSpace(getPrevRune(content, i)) && (after < '0' || after > '9') {
+				next = i
+			}
+		}
+		nextCloser[i] = next
+	}
+
+	return func(chars []byte, index int) int {
+		if chars[index] != '$' {
+			return -1
+		}
+
+		next := getNextRune(chars, index)
+		if unicode.IsSpace(next) || next == 0 {
+			return -1
+		}
+
+		// The closer must come after the character following the opener
+		start := index + 1
+		for start < len(chars) && chars[start] == PlaceholderByte {
+			start++
+		}
+		if nextCloser[start+1] >= 0 {
+			return 1
+		}
+		return -1
+	}
+}
+
 // IsBackslash handles escaped backslashes
 func IsBackslash(chars []byte, index int) int {
 	if chars[index] == '\\' {
@@ -309,3 +365,13 @@ func getNextRune(chars []byte, index int) rune {
 	}
 	return 0
 }
+
+func getPrevRune(chars []byte, index int) rune {
+	for i := index - 1; i >= 0; i-- {
+		if chars[i] == PlaceholderByte {
+			continue
+		}
+		return rune(chars[i])
+	}
+	return 0
+}
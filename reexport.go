@@ -4,38 +4,99 @@ import "github.com/thorstenpfister/semantic-markdown/types"
 
 // Re-export types for convenience
 type (
-	Node               = types.Node
-	TextNode           = types.TextNode
-	BoldNode           = types.BoldNode
-	ItalicNode         = types.ItalicNode
-	StrikethroughNode  = types.StrikethroughNode
-	HeadingNode        = types.HeadingNode
-	LinkNode           = types.LinkNode
-	ImageNode          = types.ImageNode
-	VideoNode          = types.VideoNode
-	ListNode           = types.ListNode
-	ListItemNode       = types.ListItemNode
-	TableNode          = types.TableNode
-	TableRowNode       = types.TableRowNode
-	TableCellNode      = types.TableCellNode
-	CodeNode           = types.CodeNode
-	BlockquoteNode     = types.BlockquoteNode
-	SemanticHTMLNode   = types.SemanticHTMLNode
-	MetaDataNode       = types.MetaDataNode
-	CustomNode         = types.CustomNode
-	ConversionOptions  = types.ConversionOptions
-	MetaDataMode       = types.MetaDataMode
-	EscapeMode         = types.EscapeMode
-	ElementProcessor   = types.ElementProcessor
-	NodeRenderer       = types.NodeRenderer
-	CustomNodeRenderer = types.CustomNodeRenderer
+	Node                  = types.Node
+	TextNode              = types.TextNode
+	BoldNode              = types.BoldNode
+	ItalicNode            = types.ItalicNode
+	StrikethroughNode     = types.StrikethroughNode
+	SuperscriptNode       = types.SuperscriptNode
+	SubscriptNode         = types.SubscriptNode
+	HeadingNode           = types.HeadingNode
+	LinkNode              = types.LinkNode
+	ImageNode             = types.ImageNode
+	VideoNode             = types.VideoNode
+	AudioNode             = types.AudioNode
+	EmbedNode             = types.EmbedNode
+	FigureNode            = types.FigureNode
+	DetailsNode           = types.DetailsNode
+	ListNode              = types.ListNode
+	ListItemNode          = types.ListItemNode
+	DefinitionListNode    = types.DefinitionListNode
+	DefinitionItemNode    = types.DefinitionItemNode
+	TableNode             = types.TableNode
+	TableRowNode          = types.TableRowNode
+	TableCellNode         = types.TableCellNode
+	CodeNode              = types.CodeNode
+	MathNode              = types.MathNode
+	ThematicBreakNode     = types.ThematicBreakNode
+	FootnoteReferenceNode = types.FootnoteReferenceNode
+	FootnoteNode          = types.FootnoteNode
+	ParagraphNode         = types.ParagraphNode
+	BlockquoteNode        = types.BlockquoteNode
+	SemanticHTMLNode      = types.SemanticHTMLNode
+	MetaDataNode          = types.MetaDataNode
+	CustomNode            = types.CustomNode
+	ConversionOptions     = types.ConversionOptions
+	MetaDataMode          = types.MetaDataMode
+	EscapeMode            = types.EscapeMode
+	DefinitionListStyle   = types.DefinitionListStyle
+	ScriptStyle           = types.ScriptStyle
+	TableSpanMode         = types.TableSpanMode
+	TableFormat           = types.TableFormat
+	FigureCaptionStyle    = types.FigureCaptionStyle
+	DetailsStyle          = types.DetailsStyle
+	SemanticPolicy        = types.SemanticPolicy
+	SemanticPreset        = types.SemanticPreset
+	ElementProcessor      = types.ElementProcessor
+	CodeLanguageDetector  = types.CodeLanguageDetector
+	NodeRenderer          = types.NodeRenderer
+	CustomNodeRenderer    = types.CustomNodeRenderer
+	Chunk                 = types.Chunk
+	ChunkOptions          = types.ChunkOptions
 )
 
 // Re-export constants
 const (
-	MetaDataNone       = types.MetaDataNone
-	MetaDataBasic      = types.MetaDataBasic
-	MetaDataExtended   = types.MetaDataExtended
-	EscapeModeSmart    = types.EscapeModeSmart
-	EscapeModeDisabled = types.EscapeModeDisabled
+	MetaDataNone            = types.MetaDataNone
+	MetaDataBasic           = types.MetaDataBasic
+	MetaDataExtended        = types.MetaDataExtended
+	EscapeModeSmart         = types.EscapeModeSmart
+	EscapeModeDisabled      = types.EscapeModeDisabled
+	DefaultChunkSize        = types.DefaultChunkSize
+	DefinitionListBold      = types.DefinitionListBold
+	DefinitionListExtra     = types.DefinitionListExtra
+	ScriptStyleCaret        = types.ScriptStyleCaret
+	ScriptStyleHTML         = types.ScriptStyleHTML
+	ScriptStyleUnicode      = types.ScriptStyleUnicode
+	TableSpanAnnotate       = types.TableSpanAnnotate
+	TableSpanDuplicate      = types.TableSpanDuplicate
+	TableSpanEmpty          = types.TableSpanEmpty
+	TableFormatPipe         = types.TableFormatPipe
+	TableFormatHTML         = types.TableFormatHTML
+	TableFormatRecords      = types.TableFormatRecords
+	TableFormatCSV          = types.TableFormatCSV
+	TableFormatJSON         = types.TableFormatJSON
+	TableFormatAuto         = types.TableFormatAuto
+	FigureCaptionItalic     = types.FigureCaptionItalic
+	FigureCaptionAlt        = types.FigureCaptionAlt
+	DetailsHTML             = types.DetailsHTML
+	DetailsBold             = types.DetailsBold
+	DetailsHeading          = types.DetailsHeading
+	SemanticUnwrap          = types.SemanticUnwrap
+	SemanticComment         = types.SemanticComment
+	SemanticRule            = types.SemanticRule
+	SemanticDrop            = types.SemanticDrop
+	SemanticHeading         = types.SemanticHeading
+	SemanticBlockquote      = types.SemanticBlockquote
+	SemanticPresetDefault   = types.SemanticPresetDefault
+	SemanticPresetMinimal   = types.SemanticPresetMinimal
+	SemanticPresetStructure = types.SemanticPresetStructure
+)
+
+// Re-export limit errors
+var (
+	ErrInputTooLarge    = types.ErrInputTooLarge
+	ErrMaxDepthExceeded = types.ErrMaxDepthExceeded
+	ErrMaxNodesExceeded = types.ErrMaxNodesExceeded
+	ErrOutputTooLarge   = types.ErrOutputTooLarge
 )
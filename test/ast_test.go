@@ -0,0 +1,114 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
+
+func TestParseRenderMatchesConvert(t *testing.T) {
+	htmlStr := `
+	<html>
+	<head><meta name="description" content="Test page"></head>
+	<body>
+		<h1>Title</h1>
+		<p>Text with <a href="https://example.com/very/long/path/to/page">a link</a>.</p>
+		<ul><li>One</li><li>Two</li></ul>
+	</body>
+	</html>
+	`
+
+	newOpts := func() *semanticmd.ConversionOptions {
+		return &semanticmd.ConversionOptions{
+			IncludeMetaData: semanticmd.MetaDataBasic,
+			RefifyURLs:      true,
+		}
+	}
+
+	expected, err := semanticmd.ConvertString(htmlStr, newOpts())
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	opts := newOpts()
+	nodes, err := semanticmd.Parse(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if _, ok := nodes[0].(*semanticmd.MetaDataNode); !ok {
+		t.Errorf("Expected leading MetaDataNode, got %T", nodes[0])
+	}
+
+	actual, err := semanticmd.Render(nodes, opts)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if actual != expected {
+		t.Errorf("Parse+Render differs from ConvertString\n\nExpected:\n%s\n\nActual:\n%s", expected, actual)
+	}
+
+	if opts.URLMap["ref0"] == "" {
+		t.Errorf("Expected URLMap to be propagated from Render, got %v", opts.URLMap)
+	}
+}
+
+func TestParseTransformRender(t *testing.T) {
+	htmlStr := `<h1>Title</h1><p><a href="/old">Link</a></p><p>Drop me</p>`
+
+	nodes, err := semanticmd.Parse(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	transformed := types.Rewrite(nodes, func(node semanticmd.Node) []semanticmd.Node {
+		switch n := node.(type) {
+		case *semanticmd.LinkNode:
+			n.Href = "/new"
+		case *semanticmd.TextNode:
+			if n.Content == "Drop me" {
+				return nil
+			}
+		}
+		return []semanticmd.Node{node}
+	})
+	transformed = append(transformed, &semanticmd.CustomNode{Content: "injected"})
+
+	opts := &semanticmd.ConversionOptions{
+		RenderCustomNode: func(node *semanticmd.CustomNode, opts *semanticmd.ConversionOptions, indentLevel int) string {
+			return "\n\n" + node.Content.(string)
+		},
+	}
+
+	result, err := semanticmd.Render(transformed, opts)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if !strings.Contains(result, "[Link](/new)") {
+		t.Errorf("Expected rewritten link, got:\n%s", result)
+	}
+	if strings.Contains(result, "Drop me") {
+		t.Errorf("Expected dropped node to be absent, got:\n%s", result)
+	}
+	if !strings.HasSuffix(result, "injected") {
+		t.Errorf("Expected injected custom node, got:\n%s", result)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := semanticmd.Parse("", nil); err == nil {
+		t.Error("Expected error for empty input")
+	}
+
+	opts := &semanticmd.ConversionOptions{EscapeMode: "bogus"}
+	if _, err := semanticmd.Parse("<p>x</p>", opts); err == nil {
+		t.Error("Expected error for invalid options in Parse")
+	}
+	if _, err := semanticmd.Render(nil, opts); err == nil {
+		t.Error("Expected error for invalid options in Render")
+	}
+}
@@ -92,6 +92,17 @@ func BenchmarkConvertWithEscaping(b *testing.B) {
 	}
 }
 
+func BenchmarkConvertManyDollars(b *testing.B) {
+	htmlStr := "<p>" + strings.Repeat("$a ", 100000) + "</p>"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := semanticmd.ConvertString(htmlStr, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func BenchmarkConvertWithoutEscaping(b *testing.B) {
 	html := `<html><body><p>Text with *asterisks* and #hashes# and [brackets].</p></body></html>`
 	opts := &semanticmd.ConversionOptions{
@@ -0,0 +1,182 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestChunkHeadingBreadcrumbs(t *testing.T) {
+	htmlStr := `
+	<h1>Guide</h1>
+	<p>Intro text.</p>
+	<h2>Install</h2>
+	<p>Run the installer.</p>
+	<h3>Linux</h3>
+	<p>Use the package manager.</p>
+	<h2>Usage</h2>
+	<p>Call the function.</p>
+	`
+
+	chunks, err := semanticmd.ChunkString(htmlStr, nil, nil)
+	if err != nil {
+		t.Fatalf("ChunkString failed: %v", err)
+	}
+
+	want := [][]string{
+		{"Guide"},
+		{"Guide", "Install"},
+		{"Guide", "Install", "Linux"},
+		{"Guide", "Usage"},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("Expected %d chunks, got %d: %+v", len(want), len(chunks), chunks)
+	}
+	for i, chunk := range chunks {
+		if strings.Join(chunk.Headings, " > ") != strings.Join(want[i], " > ") {
+			t.Errorf("Chunk %d: expected breadcrumb %v, got %v", i, want[i], chunk.Headings)
+		}
+	}
+
+	if !strings.HasPrefix(chunks[2].Content, "### Linux") {
+		t.Errorf("Expected chunk to start with its heading, got:\n%s", chunks[2].Content)
+	}
+}
+
+func TestChunkSizeBudget(t *testing.T) {
+	htmlStr := `
+	<h1>Title</h1>
+	<ul><li>First item with some text</li><li>Second item with some text</li></ul>
+	<pre><code>line one
+line two
+line three</code></pre>
+	<table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>
+	`
+
+	chunks, err := semanticmd.ChunkString(htmlStr, nil, &semanticmd.ChunkOptions{MaxSize: 40})
+	if err != nil {
+		t.Fatalf("ChunkString failed: %v", err)
+	}
+
+	if len(chunks) < 3 {
+		t.Fatalf("Expected content split into several chunks, got %d", len(chunks))
+	}
+
+	// Blocks must never be split across chunks
+	var code, list, table bool
+	for _, chunk := range chunks {
+		if strings.Count(chunk.Content, "```")%2 != 0 {
+			t.Errorf("Code fence split across chunks:\n%s", chunk.Content)
+		}
+		if strings.Contains(chunk.Content, "line one") {
+			code = strings.Contains(chunk.Content, "line three")
+		}
+		if strings.Contains(chunk.Content, "First item") {
+			list = strings.Contains(chunk.Content, "Second item")
+		}
+		if strings.Contains(chunk.Content, "| A | B |") {
+			table = strings.Contains(chunk.Content, "| 1 | 2 |")
+		}
+		if got := chunk.Headings; len(got) != 1 || got[0] != "Title" {
+			t.Errorf("Expected breadcrumb [Title], got %v", got)
+		}
+	}
+	if !code || !list || !table {
+		t.Errorf("Expected code, list and table kept intact (code=%v list=%v table=%v)", code, list, table)
+	}
+}
+
+func TestChunkKeepsParagraphsTogether(t *testing.T) {
+	htmlStr := `<h1>Title</h1><p>The quick brown fox <b>jumps</b> over the lazy dog and keeps on running.</p><pre><code>code</code></pre>`
+
+	chunks, err := semanticmd.ChunkString(htmlStr, nil, &semanticmd.ChunkOptions{MaxSize: 40})
+	if err != nil {
+		t.Fatalf("ChunkString failed: %v", err)
+	}
+
+	paragraph := "The quick brown fox **jumps** over the lazy dog and keeps on running."
+	found := false
+	for _, chunk := range chunks {
+		if strings.HasPrefix(chunk.Content, " ") {
+			t.Errorf("Chunk starts with a space: %q", chunk.Content)
+		}
+		if strings.Contains(chunk.Content, paragraph) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected the paragraph in one chunk, got %+v", chunks)
+	}
+}
+
+func TestChunkSectionBoundaries(t *testing.T) {
+	htmlStr := `
+	<p>Before</p>
+	<section><h2>Inside</h2><p>Section body</p></section>
+	<p>After</p>
+	`
+
+	chunks, err := semanticmd.ChunkString(htmlStr, nil, nil)
+	if err != nil {
+		t.Fatalf("ChunkString failed: %v", err)
+	}
+
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks, got %d: %+v", len(chunks), chunks)
+	}
+	if len(chunks[2].Headings) != 0 {
+		t.Errorf("Heading inside section should not leak past it, got %v", chunks[2].Headings)
+	}
+	if strings.Contains(chunks[1].Content, "---") {
+		t.Errorf("Section rules should not be rendered inside chunks:\n%s", chunks[1].Content)
+	}
+}
+
+func TestChunkMetadata(t *testing.T) {
+	htmlStr := `
+	<html>
+	<head><meta name="description" content="Page summary"></head>
+	<body><h1>One</h1><p>First</p><h1>Two</h1><p>Second</p></body>
+	</html>
+	`
+
+	opts := &semanticmd.ConversionOptions{
+		IncludeMetaData: semanticmd.MetaDataBasic,
+	}
+	chunkOpts := &semanticmd.ChunkOptions{
+		IncludeFrontmatter: true,
+	}
+
+	chunks, err := semanticmd.ChunkString(htmlStr, opts, chunkOpts)
+	if err != nil {
+		t.Fatalf("ChunkString failed: %v", err)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.Metadata == nil || chunk.Metadata.Standard["description"] != "Page summary" {
+			t.Errorf("Chunk %d: expected metadata to be carried, got %+v", i, chunk.Metadata)
+		}
+		if !strings.HasPrefix(chunk.Content, "---\ndescription: Page summary\n---") {
+			t.Errorf("Chunk %d: expected frontmatter prefix, got:\n%s", i, chunk.Content)
+		}
+	}
+}
+
+func TestChunkSizeFunc(t *testing.T) {
+	htmlStr := `<h1>Words</h1><p>one two three</p><p>four five six</p>`
+
+	words := func(s string) int { return len(strings.Fields(s)) }
+
+	chunks, err := semanticmd.ChunkString(htmlStr, nil, &semanticmd.ChunkOptions{MaxSize: 5, SizeFunc: words})
+	if err != nil {
+		t.Fatalf("ChunkString failed: %v", err)
+	}
+
+	if len(chunks) != 2 {
+		t.Fatalf("Expected 2 chunks with a 5-word budget, got %d: %+v", len(chunks), chunks)
+	}
+}
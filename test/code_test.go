@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	semanticmd "github.com/thorstenpfister/semantic-markdown"
+	"golang.org/x/net/html"
 )
 
 func TestInlineCode(t *testing.T) {
@@ -47,3 +48,449 @@ func TestCodeBlockWithLanguage(t *testing.T) {
 		t.Errorf("Expected javascript language, got: %s", result)
 	}
 }
+
+func TestCodeLanguageDetection(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"highlight.js", `<pre><code class="hljs python">x = 1</code></pre>`, "python"},
+		{"prism on pre", `<pre class="language-ruby line-numbers"><code>x = 1</code></pre>`, "ruby"},
+		{"github container", `<div class="highlight highlight-source-go"><pre>x := 1</pre></div>`, "go"},
+		{"data-lang", `<pre data-lang="rust"><code>let x = 1;</code></pre>`, "rust"},
+		{"sphinx container", `<div class="highlight-python notranslate"><div class="highlight"><pre>x = 1</pre></div></div>`, "python"},
+		{"pandoc", `<pre class="sourceCode haskell"><code class="sourceCode haskell">x = 1</code></pre>`, "haskell"},
+		{"syntaxhighlighter", `<pre class="brush: js;">x = 1</pre>`, "js"},
+		{"jekyll rouge", `<div class="language-yaml highlighter-rouge"><div class="highlight"><pre class="highlight"><code>x: 1</code></pre></div></div>`, "yaml"},
+		{"pygments without language", `<div class="highlight"><pre>x = 1</pre></div>`, ""},
+		{"nohighlight", `<pre><code class="nohighlight">x = 1</code></pre>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if !strings.HasPrefix(result, "```"+tt.expected+"\n") {
+				t.Errorf("Expected language %q, got: %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCodeLanguageGuess(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"#!/usr/bin/env python3\nprint(1)", "python"},
+		{"package main\n\nfunc main() {}", "go"},
+		{"def add(a, b):\n    return a + b", "python"},
+		{"SELECT * FROM users WHERE id = 1", "sql"},
+		{`{"name": "value"}`, "json"},
+		{"const x = 1;\nconsole.log(x);", "javascript"},
+		{"#include &lt;stdio.h&gt;\nint main() {}", "c"},
+		{"&lt;?php echo 1;", "php"},
+		{"Just some text", ""},
+	}
+
+	opts := &semanticmd.ConversionOptions{GuessCodeLanguage: true}
+	for _, tt := range tests {
+		result, err := semanticmd.ConvertString("<pre>"+tt.code+"</pre>", opts)
+		if err != nil {
+			t.Fatalf("Conversion failed: %v", err)
+		}
+		if !strings.HasPrefix(result, "```"+tt.expected+"\n") {
+			t.Errorf("Expected language %q for %q, got: %s", tt.expected, tt.code, result)
+		}
+	}
+
+	// Without GuessCodeLanguage the content is not inspected
+	result, err := semanticmd.ConvertString("<pre>package main</pre>", nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if !strings.HasPrefix(result, "```\n") {
+		t.Errorf("Expected no language without GuessCodeLanguage, got: %s", result)
+	}
+}
+
+func TestCustomCodeLanguageDetector(t *testing.T) {
+	opts := &semanticmd.ConversionOptions{
+		DetectCodeLanguage: func(pre *html.Node, code string) string {
+			if strings.HasPrefix(code, "SPARQL") {
+				return "sparql"
+			}
+			return ""
+		},
+	}
+
+	result, err := semanticmd.ConvertString(`<pre>SPARQL query</pre><pre><code class="language-go">x</code></pre>`, opts)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+	if !strings.Contains(result, "```sparql\n") || !strings.Contains(result, "```go\n") {
+		t.Errorf("Expected custom and built-in detection, got: %s", result)
+	}
+}
+
+func TestCodeFenceGolden(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "backticks in inline code",
+			html:     "<p><code>a`b</code></p>",
+			expected: "``a`b``",
+		},
+		{
+			name:     "inline code starting with a backtick",
+			html:     "<p><code>`tick</code></p>",
+			expected: "`` `tick ``",
+		},
+		{
+			name:     "inline code with a double backtick run",
+			html:     "<p><code>x `` y</code></p>",
+			expected: "`x `` y`",
+		},
+		{
+			name:     "inline code with spaces at both ends",
+			html:     "<p><code> padded </code></p>",
+			expected: "`  padded  `",
+		},
+		{
+			name:     "fence inside code block",
+			html:     "<pre><code class=\"language-markdown\">```go\nx := 1\n```</code></pre>",
+			expected: "~~~markdown\n```go\nx := 1\n```\n~~~",
+		},
+		{
+			name:     "backtick and tilde fences inside code block",
+			html:     "<pre><code>~~~\n````\n~~~</code></pre>",
+			expected: "`````\n~~~\n````\n~~~\n`````",
+		},
+		{
+			name:     "shell heredoc",
+			html:     "<pre><code class=\"language-bash\">cat &lt;&lt;EOF &gt; README.md\n```\ncode\n```\nEOF</code></pre>",
+			expected: "~~~bash\ncat <<EOF > README.md\n```\ncode\n```\nEOF\n~~~",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestCodeFenceKeepsFollowingContent(t *testing.T) {
+	html := "<pre><code>```\nunclosed</code></pre><h2>After</h2>"
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	blocks := strings.SplitN(result, "\n\n", 2)
+	if _, content, ok := parseFencedCode(blocks[0]); !ok || content != "```\nunclosed" {
+		t.Errorf("Expected a closed code block, got:\n%s", result)
+	}
+	if len(blocks) < 2 || strings.TrimSpace(blocks[1]) != "## After" {
+		t.Errorf("Expected heading after the code block, got:\n%s", result)
+	}
+}
+
+// codeFenceCorpus seeds the round-trip fuzz tests.
+var codeFenceCorpus = []string{
+	"",
+	"plain",
+	"`",
+	"``",
+	"```",
+	"a`b``c```d",
+	"```go\nfunc main() {}\n```",
+	"~~~\n```\n~~~",
+	"````\n```\n``",
+	"  ```\n",
+	" leading and trailing ",
+	"`edge`",
+	"line\n\nbreak",
+	"\x1a",
+	"~~~~~~",
+}
+
+func FuzzCodeBlockRoundTrip(f *testing.F) {
+	for _, seed := range codeFenceCorpus {
+		f.Add(seed, "go")
+	}
+	f.Add("```", "~")
+	f.Add("x", "`js`")
+
+	f.Fuzz(func(t *testing.T, content, language string) {
+		md, err := semanticmd.Render([]semanticmd.Node{&semanticmd.CodeNode{Content: content, Language: language}}, nil)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		if content == "" && md == "" {
+			return
+		}
+
+		// Rendering trims the document, so compare without surrounding blank lines
+		_, got, ok := parseFencedCode(md)
+		if !ok {
+			t.Fatalf("Code block not closed by its fence:\n%s", md)
+		}
+		if strings.Trim(got, "\n") != strings.Trim(sanitizeSUB(content), "\n") {
+			t.Errorf("Round trip mismatch:\ncontent: %q\ngot:     %q\nmarkdown:\n%s", content, got, md)
+		}
+	})
+}
+
+func FuzzCodeSpanRoundTrip(f *testing.F) {
+	for _, seed := range codeFenceCorpus {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		md, err := semanticmd.Render([]semanticmd.Node{&semanticmd.CodeNode{Content: content, Inline: true}}, nil)
+		if err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+
+		// Line breaks inside code spans are spaces in CommonMark
+		want := strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(sanitizeSUB(content))
+		if strings.TrimSpace(want) == "" {
+			// Rendering trims whitespace-only documents
+			return
+		}
+
+		got, ok := parseCodeSpan(md)
+		if !ok {
+			t.Fatalf("Not a single code span: %q", md)
+		}
+		if got != want {
+			t.Errorf("Round trip mismatch:\ncontent: %q\ngot:     %q\nmarkdown: %q", want, got, md)
+		}
+	})
+}
+
+// sanitizeSUB replaces the ASCII SUB character, which the escaper reserves
+// as its placeholder, the way rendering does.
+func sanitizeSUB(s string) string {
+	return strings.ReplaceAll(s, "\x1a", "\ufffd")
+}
+
+// parseFencedCode parses md as a single CommonMark fenced code block and
+// returns its info string and content. Reports false if the block is not
+// closed by its fence or anything but whitespace follows the fence.
+func parseFencedCode(md string) (info, content string, ok bool) {
+	lines := strings.Split(md, "\n")
+	opening := lines[0]
+	if opening == "" {
+		return "", "", false
+	}
+	fenceChar := opening[:1]
+	if fenceChar != "`" && fenceChar != "~" {
+		return "", "", false
+	}
+	fenceLen := len(opening) - len(strings.TrimLeft(opening, fenceChar))
+	if fenceLen < 3 {
+		return "", "", false
+	}
+	info = strings.TrimSpace(opening[fenceLen:])
+	if fenceChar == "`" && strings.Contains(info, "`") {
+		return "", "", false
+	}
+
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " ")
+		if len(lines[i])-len(line) > 3 {
+			continue
+		}
+		run := len(line) - len(strings.TrimLeft(line, fenceChar))
+		if run >= fenceLen && strings.TrimSpace(line[run:]) == "" {
+			// Closing fence: only whitespace may follow
+			if strings.TrimSpace(strings.Join(lines[i+1:], "\n")) != "" {
+				return "", "", false
+			}
+			return info, strings.Join(lines[1:i], "\n"), true
+		}
+	}
+	return "", "", false
+}
+
+// parseCodeSpan parses md as a single CommonMark code span and returns its
+// content. Reports false if md is not exactly one code span.
+func parseCodeSpan(md string) (string, bool) {
+	n := len(md) - len(strings.TrimLeft(md, "`"))
+	if n == 0 {
+		return "", false
+	}
+
+	// The span closes at the first backtick run of exactly n
+	rest := md[n:]
+	for i := 0; i < len(rest); {
+		if rest[i] != '`' {
+			i++
+			continue
+		}
+		run := len(rest[i:]) - len(strings.TrimLeft(rest[i:], "`"))
+		if run == n {
+			if rest[i+run:] != "" {
+				return "", false
+			}
+			content := rest[:i]
+			if len(content) >= 2 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.Trim(content, " ") != "" {
+				content = content[1 : len(content)-1]
+			}
+			return content, true
+		}
+		i += run
+	}
+	return "", false
+}
+
+func TestHighlightedCodeCleanup(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name: "pygments line number table",
+			html: `<div class="highlight-python"><table class="highlighttable"><tr><td class="linenos"><div class="linenodiv"><pre>1
+2</pre></div></td><td class="code"><div class="highlight"><pre><span class="k">def</span> f():
+    <span class="k">pass</span>
+</pre></div></td></tr></table></div>`,
+			expected: "```python\ndef f():\n    pass\n```",
+		},
+		{
+			name: "github blob table",
+			html: `<table class="highlight js-file-line-container">
+				<tr><td class="blob-num js-line-number" data-line-number="1"></td><td class="blob-code blob-code-inner">package main</td></tr>
+				<tr><td class="blob-num js-line-number" data-line-number="2"></td><td class="blob-code blob-code-inner"></td></tr>
+				<tr><td class="blob-num js-line-number" data-line-number="3"></td><td class="blob-code blob-code-inner">func main() {}</td></tr>
+			</table>`,
+			expected: "```go\npackage main\n\nfunc main() {}\n```",
+		},
+		{
+			name:     "shiki line spans",
+			html:     `<pre class="shiki"><code><span class="line"><span>const a = 1</span></span><span class="line"><span>const b = 2</span></span></code></pre>`,
+			expected: "```javascript\nconst a = 1\nconst b = 2\n```",
+		},
+		{
+			name:     "prism line-numbers plugin",
+			html:     `<pre class="language-js line-numbers"><code class="language-js">a()<br>b()<span aria-hidden="true" class="line-numbers-rows"><span></span><span></span></span></code></pre>`,
+			expected: "```js\na()\nb()\n```",
+		},
+		{
+			name:     "hexo gutter",
+			html:     `<figure class="highlight js"><table><tr><td class="gutter"><pre><span class="line">1</span><br><span class="line">2</span><br></pre></td><td class="code"><pre><span class="line">a()</span><br><span class="line">b()</span><br></pre></td></tr></table></figure>`,
+			expected: "```js\na()\nb()\n```",
+		},
+		{
+			name: "chroma line number table",
+			html: `<div class="chroma"><table class="lntable"><tr><td class="lntd"><pre class="chroma"><code><span class="lnt">1
+</span><span class="lnt">2
+</span></code></pre></td><td class="lntd"><pre class="chroma"><code class="language-go"><span class="line"><span class="cl">x := 1
+</span></span><span class="line"><span class="cl">y := 2</span></span></code></pre></td></tr></table></div>`,
+			expected: "```go\nx := 1\ny := 2\n```",
+		},
+		{
+			name:     "highlight.js line numbers",
+			html:     `<pre><code class="hljs go"><table class="hljs-ln"><tbody><tr><td class="hljs-ln-line hljs-ln-numbers"><div class="hljs-ln-n" data-line-number="1"></div></td><td class="hljs-ln-line hljs-ln-code">a := 1</td></tr><tr><td class="hljs-ln-line hljs-ln-numbers"><div class="hljs-ln-n" data-line-number="2"></div></td><td class="hljs-ln-line hljs-ln-code">b := 2</td></tr></tbody></table></code></pre>`,
+			expected: "```go\na := 1\nb := 2\n```",
+		},
+		{
+			name:     "copy buttons",
+			html:     `<div class="code-wrap"><button class="copy-btn">Copy</button><pre><button aria-label="Copy code">Copy</button><code>x</code></pre></div>`,
+			expected: "```\nx\n```",
+		},
+		{
+			name:     "toolbar copy button",
+			html:     `<div class="code-block"><pre><code>x</code></pre><div class="toolbar"><button title="Copy to clipboard"><svg></svg></button></div></div>`,
+			expected: "```\nx\n```",
+		},
+		{
+			name:     "buttons away from code are kept",
+			html:     `<p>See <button>Buy a copy</button> now</p>`,
+			expected: "See Buy a copy now",
+		},
+		{
+			name:     "copy as part of a word",
+			html:     `<div><pre><code>x</code></pre><button>Copyright</button></div>`,
+			expected: "```\nx\n```\n\nCopyright",
+		},
+	}
+
+	opts := &semanticmd.ConversionOptions{GuessCodeLanguage: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, opts)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestPlainTableIsNotCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		contains []string
+	}{
+		{
+			name:     "numeric first column",
+			html:     `<table><tr><td>1</td><td>a</td></tr></table>`,
+			contains: []string{"| 1 | a |"},
+		},
+		{
+			name: "copy cells",
+			html: `<table><tr><th>Name</th><th>Price</th><th>Action</th></tr>` +
+				`<tr><td>Widget</td><td>10</td><td class="copy">Copy</td></tr>` +
+				`<tr><td>Gadget</td><td>25</td><td class="copy">Copy</td></tr></table>`,
+			contains: []string{"| Name | Price | Action |", "| Widget | 10 | Copy |"},
+		},
+		{
+			name: "email layout gutter",
+			html: `<table><tr><td class="gutter">&nbsp;</td><td>Hello <b>reader</b></td>` +
+				`<td class="gutter">&nbsp;</td></tr></table>`,
+			contains: []string{"Hello **reader**"},
+		},
+		{
+			name: "numeric data",
+			html: `<table><tr><td class="ln">1</td><td>2</td><td>3</td></tr>` +
+				`<tr><td class="ln">4</td><td>5</td><td>6</td></tr></table>`,
+			contains: []string{"| 1 | 2 | 3 |", "| 4 | 5 | 6 |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if strings.Contains(result, "```") {
+				t.Errorf("Expected a table, got:\n%s", result)
+			}
+			for _, want := range tt.contains {
+				if !strings.Contains(result, want) {
+					t.Errorf("Expected %q in:\n%s", want, result)
+				}
+			}
+		})
+	}
+}
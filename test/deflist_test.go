@@ -0,0 +1,108 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestDefinitionList(t *testing.T) {
+	htmlStr := `
+	<dl>
+		<dt>API</dt>
+		<dd>Application programming interface</dd>
+		<dt>CLI</dt>
+		<dd>Command line interface</dd>
+	</dl>
+	`
+
+	result, err := semanticmd.ConvertString(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	expected := `**API**
+  Application programming interface
+
+**CLI**
+  Command line interface`
+
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestDefinitionListExtraStyle(t *testing.T) {
+	htmlStr := `
+	<dl>
+		<div><dt>Term</dt><dd>First definition</dd><dd>Second definition</dd></div>
+		<div><dt>Other</dt><dd>Another</dd></div>
+	</dl>
+	`
+
+	opts := &semanticmd.ConversionOptions{
+		DefinitionListStyle: semanticmd.DefinitionListExtra,
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	expected := `Term
+: First definition
+: Second definition
+
+Other
+: Another`
+
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestDefinitionListExtraContinuation(t *testing.T) {
+	htmlStr := `<dl><dt>Term</dt><dd><p>First</p><ul><li>a</li></ul></dd></dl>`
+
+	opts := &semanticmd.ConversionOptions{
+		DefinitionListStyle: semanticmd.DefinitionListExtra,
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	expected := "Term\n: First\n\n    - a"
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestDefinitionListRefification(t *testing.T) {
+	htmlStr := `<dl><dt>Docs</dt><dd><a href="https://example.com/very/long/path/to/docs">Reference</a></dd></dl>`
+
+	opts := &semanticmd.ConversionOptions{
+		RefifyURLs: true,
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	if !strings.Contains(result, "[Reference](ref0)") {
+		t.Errorf("Expected link inside definition to be refified:\n%s", result)
+	}
+}
+
+func TestDefinitionListInvalidStyle(t *testing.T) {
+	opts := &semanticmd.ConversionOptions{
+		DefinitionListStyle: "bogus",
+	}
+
+	if _, err := semanticmd.ConvertString("<dl></dl>", opts); err == nil {
+		t.Error("Expected error for invalid DefinitionListStyle")
+	}
+}
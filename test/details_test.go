@@ -0,0 +1,76 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestDetailsStyles(t *testing.T) {
+	htmlStr := `<details><summary>How do I install it?</summary><p>Run go install.</p></details>`
+
+	tests := []struct {
+		style    semanticmd.DetailsStyle
+		expected string
+	}{
+		{
+			style:    semanticmd.DetailsHTML,
+			expected: "<details>\n<summary>How do I install it?</summary>\n\nRun go install.\n\n</details>",
+		},
+		{
+			style:    semanticmd.DetailsBold,
+			expected: "**How do I install it?**\n\nRun go install.",
+		},
+		{
+			style:    semanticmd.DetailsHeading,
+			expected: "### How do I install it?\n\nRun go install.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{DetailsStyle: tt.style})
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDetailsOpenWithoutSummary(t *testing.T) {
+	result, err := semanticmd.ConvertString(`<details open><p>Body</p></details>`, nil)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	expected := "<details open>\n<summary>Details</summary>\n\nBody\n\n</details>"
+	if strings.TrimSpace(result) != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestDetailsAST(t *testing.T) {
+	nodes, err := semanticmd.Parse(`<details><summary>Q</summary><p>A</p></details>`, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	details, ok := nodes[0].(*semanticmd.DetailsNode)
+	if !ok {
+		t.Fatalf("Expected DetailsNode, got %T", nodes[0])
+	}
+	if len(details.Summary) != 1 || len(details.Content) != 1 {
+		t.Errorf("Expected one summary and one content node, got %d and %d", len(details.Summary), len(details.Content))
+	}
+}
+
+func TestInvalidDetailsStyle(t *testing.T) {
+	_, err := semanticmd.ConvertString("<details></details>", &semanticmd.ConversionOptions{DetailsStyle: "list"})
+	if err == nil {
+		t.Error("Expected error for invalid DetailsStyle")
+	}
+}
@@ -0,0 +1,86 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestFigureCaptionStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		style    semanticmd.FigureCaptionStyle
+		expected string
+	}{
+		{
+			name:     "italic caption below image",
+			html:     `<figure><img src="/cat.png" alt="A cat"><figcaption>Figure 1</figcaption></figure>`,
+			expected: "![A cat](/cat.png)\n\n*Figure 1*",
+		},
+		{
+			name:     "caption becomes alt text",
+			html:     `<figure><img src="/cat.png"><figcaption>A cat</figcaption></figure>`,
+			style:    semanticmd.FigureCaptionAlt,
+			expected: "![A cat](/cat.png)",
+		},
+		{
+			name:     "existing alt text is kept",
+			html:     `<figure><img src="/cat.png" alt="Cat"><figcaption>Figure 1</figcaption></figure>`,
+			style:    semanticmd.FigureCaptionAlt,
+			expected: "![Cat](/cat.png)\n\n*Figure 1*",
+		},
+		{
+			name:     "caption before content",
+			html:     `<figure><figcaption>Listing 1</figcaption><pre><code>x := 1</code></pre></figure>`,
+			style:    semanticmd.FigureCaptionAlt,
+			expected: "```\nx := 1\n```\n\n*Listing 1*",
+		},
+		{
+			name:     "figure without caption",
+			html:     `<figure><img src="/cat.png" alt="Cat"></figure>`,
+			expected: "![Cat](/cat.png)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, &semanticmd.ConversionOptions{FigureCaptionStyle: tt.style})
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFigureAltDoesNotModifyAST(t *testing.T) {
+	htmlStr := `<figure><img src="/cat.png"><figcaption>A cat</figcaption></figure>`
+	opts := &semanticmd.ConversionOptions{FigureCaptionStyle: semanticmd.FigureCaptionAlt}
+
+	nodes, err := semanticmd.Parse(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if _, err := semanticmd.Render(nodes, opts); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	figure, ok := nodes[0].(*semanticmd.FigureNode)
+	if !ok {
+		t.Fatalf("Expected FigureNode, got %T", nodes[0])
+	}
+	if img := figure.Content[0].(*semanticmd.ImageNode); img.Alt != "" {
+		t.Errorf("Expected image alt to stay empty, got %q", img.Alt)
+	}
+}
+
+func TestInvalidFigureCaptionStyle(t *testing.T) {
+	_, err := semanticmd.ConvertString("<figure></figure>", &semanticmd.ConversionOptions{FigureCaptionStyle: "bold"})
+	if err == nil {
+		t.Error("Expected error for invalid FigureCaptionStyle")
+	}
+}
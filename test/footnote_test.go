@@ -0,0 +1,94 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestFootnotesWikipedia(t *testing.T) {
+	html := `
+	<p>Claim<sup id="cite_ref-1" class="reference"><a href="#cite_note-1">[1]</a></sup></p>
+	<p>Another<sup>[<a href="#cite_note-2">2</a>]</sup></p>
+	<ol class="references">
+		<li id="cite_note-1"><span class="mw-cite-backlink"><b><a href="#cite_ref-1">^</a></b></span> <span class="reference-text">Smith 2001.</span></li>
+		<li id="cite_note-2"><span class="mw-cite-backlink"><a href="#cite_ref-2">^</a></span> Jones 2002.</li>
+	</ol>
+	`
+
+	result, err := semanticmd.ConvertString(html, &semanticmd.ConversionOptions{Footnotes: true})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	for _, want := range []string{"Claim[^1]", "Another[^2]", "[^1]: Smith 2001.", "[^2]: Jones 2002."} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in output, got: %s", want, result)
+		}
+	}
+	if strings.Contains(result, "cite_") || strings.Contains(result, "^]") {
+		t.Errorf("Expected citation links and backlinks to be removed, got: %s", result)
+	}
+}
+
+func TestFootnotesPandoc(t *testing.T) {
+	html := `
+	<p>Text<a href="#fn1" class="footnote-ref" id="fnref1" role="doc-noteref"><sup>1</sup></a></p>
+	<section class="footnotes" role="doc-endnotes">
+		<ol>
+			<li id="fn1"><p>First note.<a href="#fnref1" class="footnote-back" role="doc-backlink">↩︎</a></p></li>
+		</ol>
+	</section>
+	`
+
+	result, err := semanticmd.ConvertString(html, &semanticmd.ConversionOptions{Footnotes: true})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	if !strings.Contains(result, "Text[^1]") {
+		t.Errorf("Expected footnote reference, got: %s", result)
+	}
+	if !strings.Contains(result, "[^1]: First note.") {
+		t.Errorf("Expected footnote definition, got: %s", result)
+	}
+	if strings.Contains(result, "↩") {
+		t.Errorf("Expected backlink to be removed, got: %s", result)
+	}
+}
+
+func TestFootnotesLeaveOtherFragmentLinks(t *testing.T) {
+	html := `
+	<p>See <a href="#intro">intro</a> and <a href="#missing">missing</a><sup>[<a href="#n1">1</a>]</sup></p>
+	<ol class="references"><li id="n1">Note.</li></ol>
+	`
+
+	result, err := semanticmd.ConvertString(html, &semanticmd.ConversionOptions{Footnotes: true})
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	if !strings.Contains(result, "[intro](#intro)") || !strings.Contains(result, "[missing](#missing)") {
+		t.Errorf("Expected non-footnote fragment links to be preserved, got: %s", result)
+	}
+	if !strings.Contains(result, "[^1]: Note.") {
+		t.Errorf("Expected footnote definition, got: %s", result)
+	}
+}
+
+func TestFootnotesDisabledByDefault(t *testing.T) {
+	html := `<p>Claim<sup><a href="#cite_note-1">[1]</a></sup></p><ol class="references"><li id="cite_note-1">Note.</li></ol>`
+
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	if strings.Contains(result, "[^1]") {
+		t.Errorf("Expected no footnotes without the option, got: %s", result)
+	}
+	if !strings.Contains(result, "1. Note.") {
+		t.Errorf("Expected references rendered as a list, got: %s", result)
+	}
+}
@@ -11,7 +11,20 @@ import (
 
 // TestGoldenFiles runs golden file tests
 func TestGoldenFiles(t *testing.T) {
-	cases, err := filepath.Glob("../testdata/escape/golden/*.in.html")
+	runGoldenFiles(t, "../testdata/escape/golden")
+}
+
+// TestWhitespaceCorpus runs the whitespace regression corpus
+func TestWhitespaceCorpus(t *testing.T) {
+	runGoldenFiles(t, "../testdata/whitespace")
+}
+
+// runGoldenFiles converts every *.in.html file in dir and compares the
+// result with the matching *.out.md file.
+func runGoldenFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	cases, err := filepath.Glob(filepath.Join(dir, "*.in.html"))
 	if err != nil {
 		t.Fatalf("Failed to find golden test cases: %v", err)
 	}
@@ -0,0 +1,135 @@
+package semanticmd_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
+
+func TestASTJSONRoundTrip(t *testing.T) {
+	htmlStr := `
+	<html>
+	<head>
+		<meta name="description" content="Round trip">
+		<meta property="og:title" content="OG">
+	</head>
+	<body>
+		<h1>Title <em>emph</em></h1>
+		<p><strong>Bold</strong> <s>gone</s> <a href="/x">link</a> <code>x := 1</code></p>
+		<img src="/a.png" alt="A">
+		<video src="/v.mp4" poster="/p.jpg" controls></video>
+		<audio src="/a.mp3"></audio>
+		<iframe src="/embed" title="Player"></iframe>
+		<figure><img src="/f.png"><figcaption>Caption</figcaption></figure>
+		<details open><summary>Question</summary><p>Answer</p></details>
+		<ol><li>One</li><li>Two <b>b</b></li><li><p>Three</p><p>Four</p></li></ol>
+		<table><caption>Cap</caption><tr><th>H</th></tr><tr><td colspan="2">C</td></tr><tfoot><tr><th>F</th><td>1</td></tr></tfoot></table>
+		<pre><code class="language-go">func main() {}</code></pre>
+		<p><math><mi>x</mi></math></p>
+		<math display="block"><mi>y</mi></math>
+		<blockquote>Quote</blockquote>
+		<p>x<sup>2</sup> H<sub>2</sub>O</p>
+		<hr>
+		<dl><dt>Term</dt><dd>Definition</dd></dl>
+		<nav>Nav</nav>
+	</body>
+	</html>
+	`
+
+	opts := &semanticmd.ConversionOptions{
+		IncludeMetaData:           semanticmd.MetaDataExtended,
+		EnableTableColumnTracking: true,
+	}
+
+	nodes, err := semanticmd.Parse(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	data, err := types.MarshalNodes(nodes)
+	if err != nil {
+		t.Fatalf("MarshalNodes failed: %v", err)
+	}
+
+	decoded, err := types.UnmarshalNodes(data)
+	if err != nil {
+		t.Fatalf("UnmarshalNodes failed: %v", err)
+	}
+
+	// Empty maps and slices decode as nil, so compare the re-encoded form
+	reencoded, err := types.MarshalNodes(decoded)
+	if err != nil {
+		t.Fatalf("MarshalNodes failed: %v", err)
+	}
+	if string(reencoded) != string(data) {
+		t.Errorf("AST changed across JSON round trip\n\nBefore:\n%s\n\nAfter:\n%s", data, reencoded)
+	}
+	if !reflect.DeepEqual(nodes[1], decoded[1]) {
+		t.Errorf("Expected heading to decode identically, got %#v", decoded[1])
+	}
+
+	expected, _ := semanticmd.Render(nodes, opts)
+	actual, _ := semanticmd.Render(decoded, opts)
+	if expected != actual {
+		t.Errorf("Rendering differs after round trip\n\nExpected:\n%s\n\nActual:\n%s", expected, actual)
+	}
+}
+
+func TestASTJSONTypeDiscriminator(t *testing.T) {
+	data, err := json.Marshal(&types.HeadingNode{
+		Level:   2,
+		Content: []types.Node{&types.TextNode{Content: "Hi"}},
+	})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := `{"type":"heading","level":2,"content":[{"type":"text","content":"Hi"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	node, err := types.UnmarshalNode(data)
+	if err != nil {
+		t.Fatalf("UnmarshalNode failed: %v", err)
+	}
+	if h, ok := node.(*types.HeadingNode); !ok || h.Level != 2 {
+		t.Errorf("Expected heading level 2, got %#v", node)
+	}
+}
+
+func TestASTJSONUnknownType(t *testing.T) {
+	_, err := types.UnmarshalNodes([]byte(`[{"type":"bogus"}]`))
+	if err == nil || !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("Expected unknown type error, got %v", err)
+	}
+}
+
+func TestASTJSONNullElements(t *testing.T) {
+	nodes, err := types.UnmarshalNodes([]byte(`[null,{"type":"bold","content":[null,{"type":"text","content":"x"}]}]`))
+	if err != nil {
+		t.Fatalf("UnmarshalNodes failed: %v", err)
+	}
+
+	if len(nodes) != 1 {
+		t.Fatalf("Expected null elements to be skipped, got %#v", nodes)
+	}
+	bold, ok := nodes[0].(*types.BoldNode)
+	if !ok || len(bold.Content) != 1 {
+		t.Fatalf("Expected bold node with one child, got %#v", nodes[0])
+	}
+	if text, ok := bold.Content[0].(*types.TextNode); !ok || text.Content != "x" {
+		t.Errorf("Expected text child, got %#v", bold.Content[0])
+	}
+}
+
+func TestASTJSONMissingType(t *testing.T) {
+	_, err := types.UnmarshalNodes([]byte(`[{"content":"x"}]`))
+	if err == nil || !strings.Contains(err.Error(), "no type") {
+		t.Errorf("Expected missing type error, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package semanticmd_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+	"golang.org/x/net/html"
+)
+
+func TestConvertContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := semanticmd.ConvertStringContext(ctx, "<p>Hello</p>", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestConvertContextCanceledWhileParsing(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel as soon as the first <section> is parsed
+	visited := 0
+	opts := &semanticmd.ConversionOptions{
+		OverrideElementProcessing: func(node *html.Node, _ *semanticmd.ConversionOptions, _ int) []semanticmd.Node {
+			if node.Type == html.ElementNode && node.Data == "section" {
+				visited++
+				cancel()
+			}
+			return nil
+		},
+	}
+
+	htmlStr := strings.Repeat("<section><p>Text</p></section>", 100)
+	_, err := semanticmd.ConvertStringContext(ctx, htmlStr, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if visited != 1 {
+		t.Errorf("Expected parsing to stop after the first section, visited %d", visited)
+	}
+}
+
+func TestConvertContextSucceeds(t *testing.T) {
+	result, err := semanticmd.ConvertContext(context.Background(), strings.NewReader("<h1>Hello</h1>"), nil)
+	if err != nil {
+		t.Fatalf("ConvertContext failed: %v", err)
+	}
+	if result != "# Hello" {
+		t.Errorf("Expected heading, got %q", result)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	deep := strings.Repeat("<div>", 200) + "text" + strings.Repeat("</div>", 200)
+	wide := strings.Repeat("<p>x</p>", 500)
+
+	tests := []struct {
+		name    string
+		html    string
+		opts    *semanticmd.ConversionOptions
+		wantErr error
+	}{
+		{"input bytes", wide, &semanticmd.ConversionOptions{MaxInputBytes: 100}, semanticmd.ErrInputTooLarge},
+		{"depth", deep, &semanticmd.ConversionOptions{MaxDepth: 50}, semanticmd.ErrMaxDepthExceeded},
+		{"nodes", wide, &semanticmd.ConversionOptions{MaxNodes: 100}, semanticmd.ErrMaxNodesExceeded},
+		{"output bytes", wide, &semanticmd.ConversionOptions{MaxOutputBytes: 100}, semanticmd.ErrOutputTooLarge},
+		{"within limits", deep, &semanticmd.ConversionOptions{MaxInputBytes: 1 << 20, MaxDepth: 500, MaxNodes: 1000, MaxOutputBytes: 100}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := semanticmd.ConvertString(tt.html, tt.opts)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLimitsApplyToParse(t *testing.T) {
+	deep := strings.Repeat("<div>", 100) + strings.Repeat("</div>", 100)
+
+	_, err := semanticmd.Parse(deep, &semanticmd.ConversionOptions{MaxDepth: 10})
+	if !errors.Is(err, semanticmd.ErrMaxDepthExceeded) {
+		t.Errorf("Expected ErrMaxDepthExceeded from Parse, got %v", err)
+	}
+}
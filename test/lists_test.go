@@ -39,3 +39,154 @@ func TestOrderedList(t *testing.T) {
 		t.Errorf("Expected ordered list item 3, got: %s", result)
 	}
 }
+
+func TestNestedListsOfMixedType(t *testing.T) {
+	html := `<ul><li>Fruit<ol><li>Apple</li><li>Pear<ul><li>Green</li></ul></li></ol></li><li>Veg</li></ul>`
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	expected := "- Fruit\n  1. Apple\n  2. Pear\n     - Green\n- Veg"
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestListItemWithMultipleParagraphs(t *testing.T) {
+	html := `<ol><li><p>First paragraph.</p><p>Second paragraph.</p></li><li><p>Next item.</p></li></ol>`
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	expected := "1. First paragraph.\n\n   Second paragraph.\n\n2. Next item."
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestListItemParagraphsAST(t *testing.T) {
+	nodes, err := semanticmd.Parse(`<ul><li><p>First</p><p>Second</p></li></ul>`, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	list := nodes[0].(*semanticmd.ListNode)
+	content := list.Items[0].Content
+	if len(content) != 2 {
+		t.Fatalf("Expected 2 paragraphs, got %d nodes", len(content))
+	}
+	for i, node := range content {
+		if _, ok := node.(*semanticmd.ParagraphNode); !ok {
+			t.Errorf("Node %d: expected *ParagraphNode, got %T", i, node)
+		}
+	}
+}
+
+func TestListItemWithCodeBlock(t *testing.T) {
+	html := "<ul><li>Run:<pre><code>make\nmake install</code></pre></li></ul>"
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	expected := "- Run:\n\n  ```\n  make\n  make install\n  ```"
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestOrderedListNumbering(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"start", `<ol start="5"><li>a</li><li>b</li></ol>`, "5. a\n6. b"},
+		{"reversed", `<ol reversed><li>a</li><li>b</li><li>c</li></ol>`, "3. a\n2. b\n1. c"},
+		{"reversed with start", `<ol reversed start="10"><li>a</li><li>b</li></ol>`, "10. a\n9. b"},
+		{"li value", `<ol><li>a</li><li value="7">b</li><li>c</li></ol>`, "1. a\n7. b\n8. c"},
+		{"negative start", `<ol start="-3"><li>a</li><li>b</li></ol>`, "0. a\n1. b"},
+		{"reversed below zero", `<ol reversed start="1"><li>a</li><li>b</li><li>c</li></ol>`, "1. a\n2. b\n3. c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestNestedOrderedListNotStartingAtOne(t *testing.T) {
+	// CommonMark only lets a list starting at 1 interrupt a paragraph
+	html := `<ul><li>Steps<ol start="3"><li>Third</li></ol></li></ul>`
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	expected := "- Steps\n\n  3. Third"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestStrayNestedList(t *testing.T) {
+	// A <ul> directly inside a <ul> belongs to the preceding item
+	html := `<ul><li>Parent</li><ul><li>Child</li></ul><li>Sibling</li></ul>`
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	expected := "- Parent\n  - Child\n- Sibling"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestTaskList(t *testing.T) {
+	html := `
+	<ul class="contains-task-list">
+		<li class="task-list-item"><input type="checkbox" class="task-list-item-checkbox" disabled checked> Done</li>
+		<li class="task-list-item"><label><input type="checkbox"> Todo</label>
+			<ul><li><input type="checkbox" checked>Subtask</li></ul>
+		</li>
+		<li>Plain item <input type="checkbox"></li>
+	</ul>`
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	expected := "- [x] Done\n- [ ] Todo\n  - [x] Subtask\n- Plain item"
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestTaskListAST(t *testing.T) {
+	nodes, err := semanticmd.Parse(`<ul><li><input type="checkbox" checked>A</li><li><input type="checkbox">B</li><li>C</li></ul>`, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	list, ok := nodes[0].(*semanticmd.ListNode)
+	if !ok {
+		t.Fatalf("Expected *ListNode, got %T", nodes[0])
+	}
+	want := []struct{ isTask, checked bool }{{true, true}, {true, false}, {false, false}}
+	for i, item := range list.Items {
+		if item.IsTask != want[i].isTask || item.Checked != want[i].checked {
+			t.Errorf("Item %d: expected IsTask=%v Checked=%v, got IsTask=%v Checked=%v",
+				i, want[i].isTask, want[i].checked, item.IsTask, item.Checked)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestMathSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "MathML annotation",
+			html:     `<p>Euler: <math><semantics><mrow><msup><mi>e</mi><mrow><mi>i</mi><mi>π</mi></mrow></msup></mrow><annotation encoding="application/x-tex">e^{i\pi}</annotation></semantics></math> is neat.</p>`,
+			expected: `Euler: $e^{i\pi}$ is neat.`,
+		},
+		{
+			name:     "MathML display",
+			html:     `<math display="block"><mfrac><mn>1</mn><mi>n</mi></mfrac></math>`,
+			expected: "$$\n\\frac{1}{n}\n$$",
+		},
+		{
+			name: "KaTeX inline",
+			html: `<span class="katex"><span class="katex-mathml"><math><semantics><mrow><mi>x</mi></mrow>` +
+				`<annotation encoding="application/x-tex">x^2</annotation></semantics></math></span>` +
+				`<span class="katex-html" aria-hidden="true"><span class="base">x2</span></span></span>`,
+			expected: "$x^2$",
+		},
+		{
+			name: "KaTeX display",
+			html: `<span class="katex-display"><span class="katex"><span class="katex-mathml"><math><semantics><mrow></mrow>` +
+				`<annotation encoding="application/x-tex">\int_0^1 f</annotation></semantics></math></span>` +
+				`<span class="katex-html" aria-hidden="true">∫f</span></span></span>`,
+			expected: "$$\n\\int_0^1 f\n$$",
+		},
+		{
+			name: "MathJax v2",
+			html: `<span class="MathJax_Preview">a+b</span><span class="MathJax" id="MathJax-Element-1-Frame">a+b</span>` +
+				`<script type="math/tex" id="MathJax-Element-1">a+b</script>` +
+				`<div class="MathJax_Display"><span class="MathJax">c</span></div><script type="math/tex; mode=display">c</script>`,
+			expected: "$a+b$\n\n$$\nc\n$$",
+		},
+		{
+			name:     "MathJax v3",
+			html:     `<mjx-container class="MathJax" jax="CHTML" display="true"><mjx-math>…</mjx-math><mjx-assistive-mml><math><mi>y</mi></math></mjx-assistive-mml></mjx-container>`,
+			expected: "$$\ny\n$$",
+		},
+		{
+			name:     "other scripts are ignored",
+			html:     `<p>Text</p><script type="text/javascript">var a = 1;</script>`,
+			expected: "Text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMathMLToTeX(t *testing.T) {
+	tests := []struct {
+		name     string
+		mathml   string
+		expected string
+	}{
+		{"identifiers and operators", `<mi>a</mi><mo>+</mo><mi>β</mi><mo>≤</mo><mn>2</mn>`, `a+\beta\leq2`},
+		{"command before letter", `<mi>α</mi><mi>x</mi>`, `\alpha x`},
+		{"scripts", `<msubsup><mi>x</mi><mi>i</mi><mn>10</mn></msubsup>`, `x_i^{10}`},
+		{"compound base", `<msup><mrow><mo>(</mo><mi>a</mi><mo>+</mo><mi>b</mi><mo>)</mo></mrow><mn>2</mn></msup>`, `{(a+b)}^2`},
+		{"fraction", `<mfrac><mrow><mi>a</mi><mo>+</mo><mn>1</mn></mrow><mi>b</mi></mfrac>`, `\frac{a+1}{b}`},
+		{"roots", `<msqrt><mi>x</mi></msqrt><mroot><mi>y</mi><mn>3</mn></mroot>`, `\sqrt{x}\sqrt[3]{y}`},
+		{"functions", `<mi>sin</mi><mo>⁡</mo><mi>θ</mi><mo>+</mo><mi>speed</mi>`, `\sin\theta+\mathrm{speed}`},
+		{"sum limits", `<munderover><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover>`, `\sum_{i=1}^n`},
+		{"accent", `<mover><mi>v</mi><mo>→</mo></mover>`, `\vec{v}`},
+		{"fenced", `<mfenced><mi>x</mi><mi>y</mi></mfenced>`, `\left( x,y \right)`},
+		{"matrix", `<mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>0</mn></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mn>1</mn></mtd></mtr></mtable>`, `\begin{matrix} 1 & 0 \\ 0 & 1 \end{matrix}`},
+		{"text", `<mtext>if </mtext><mi>x</mi><mo>&gt;</mo><mn>0</mn>`, `\text{if }x>0`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(`<math>`+tt.mathml+`</math>`, nil)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if expected := "$" + tt.expected + "$"; strings.TrimSpace(result) != expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+			}
+		})
+	}
+}
+
+func TestEscapingDollars(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"prices stay plain", `<p>It costs $5 and $10.</p>`, `It costs $5 and $10.`},
+		{"math-like text", `<p>Use $x$ here.</p>`, `Use \$x$ here.`},
+		{"display-like text", `<p>$$x$$</p>`, `\$\$x$$`},
+		{"spaced dollars", `<p>Between $ and $ signs.</p>`, `Between $ and $ signs.`},
+		{"separate blocks", `<h2>Pay $5</h2><p>or 5$ later</p>`, "## Pay $5\n\nor 5$ later"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestEscapingDollarsIsLinear(t *testing.T) {
+	// Many unclosed dollars in one paragraph used to take quadratic time
+	htmlStr := "<p>" + strings.Repeat("$a ", 100000) + "</p>"
+
+	done := make(chan string, 1)
+	go func() {
+		result, _ := semanticmd.ConvertString(htmlStr, nil)
+		done <- result
+	}()
+
+	select {
+	case result := <-done:
+		if strings.Contains(result, `\$`) {
+			t.Errorf("Expected unclosed dollars to stay unescaped")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Escaping dollars took too long")
+	}
+}
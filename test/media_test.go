@@ -0,0 +1,166 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestMediaElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "video with source children",
+			html:     `<video controls><source src="/clip.webm" type="video/webm"><source src="/clip.mp4"></video>`,
+			expected: "![Video](/clip.webm)\nControls: true",
+		},
+		{
+			name:     "audio",
+			html:     `<audio controls src="/talk.mp3"></audio>`,
+			expected: "![Audio](/talk.mp3)\nControls: true",
+		},
+		{
+			name:     "audio with source children",
+			html:     `<audio><source src="/talk.ogg"></audio>`,
+			expected: "![Audio](/talk.ogg)",
+		},
+		{
+			name:     "iframe",
+			html:     `<iframe src="https://www.youtube.com/embed/abc" title="Launch video"></iframe>`,
+			expected: "[Launch video](https://www.youtube.com/embed/abc)",
+		},
+		{
+			name:     "lazy iframe without title",
+			html:     `<iframe src="about:blank" data-src="https://maps.example.com/embed?q=x"></iframe>`,
+			expected: "[Embed](https://maps.example.com/embed?q=x)",
+		},
+		{
+			name:     "embed",
+			html:     `<embed src="/doc.pdf" type="application/pdf">`,
+			expected: "[Embed](/doc.pdf)",
+		},
+		{
+			name:     "object with data",
+			html:     `<object data="/chart.svg" aria-label="Chart"><p>Fallback</p></object>`,
+			expected: "[Chart](/chart.svg)",
+		},
+		{
+			name:     "object without data uses fallback content",
+			html:     `<object><p>Fallback</p></object>`,
+			expected: "Fallback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestImageSourceSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "src wins over srcset",
+			html:     `<img src="/a.jpg" srcset="/a-2x.jpg 2x" alt="A">`,
+			expected: "![A](/a.jpg)",
+		},
+		{
+			name:     "data-src replaces placeholder",
+			html:     `<img src="data:image/gif;base64,R0lGODlhAQABAAAAACw=" data-src="/real.jpg" alt="A">`,
+			expected: "![A](/real.jpg)",
+		},
+		{
+			name:     "widest srcset candidate",
+			html:     `<img srcset="/s.jpg 480w, /l.jpg 1200w, /m.jpg 800w" alt="A">`,
+			expected: "![A](/l.jpg)",
+		},
+		{
+			name:     "highest density candidate",
+			html:     `<img data-srcset="/a.jpg, /a-3x.jpg 3x, /a-2x.jpg 2x" alt="A">`,
+			expected: "![A](/a-3x.jpg)",
+		},
+		{
+			name:     "commas inside candidate URLs",
+			html:     `<img srcset="https://cdn.example.com/w_400,q_80/a.jpg 400w, https://cdn.example.com/w_800,q_80/a.jpg 800w" alt="A">`,
+			expected: "![A](https://cdn.example.com/w_800,q_80/a.jpg)",
+		},
+		{
+			name:     "placeholder kept without alternative",
+			html:     `<img src="data:image/png;base64,AAAA" alt="A">`,
+			expected: "![A](data:image/png;base64,AAAA)",
+		},
+		{
+			name:     "picture uses img src",
+			html:     `<picture><source srcset="/a.webp" type="image/webp"><img src="/a.jpg" alt="A"></picture>`,
+			expected: "![A](/a.jpg)",
+		},
+		{
+			name:     "picture falls back to source",
+			html:     `<picture><source srcset="/a-1.webp 1x, /a-2.webp 2x"><img alt="A"></picture>`,
+			expected: "![A](/a-2.webp)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestMediaURLRefification(t *testing.T) {
+	htmlStr := `<audio src="https://cdn.example.com/media/audio/talk.mp3"></audio>
+		<iframe src="https://www.example.com/embed/videos/2024/abc" title="Player"></iframe>`
+
+	opts := &semanticmd.ConversionOptions{RefifyURLs: true}
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	if strings.Contains(result, "https://") {
+		t.Errorf("Expected media URLs to be refified:\n%s", result)
+	}
+	if len(opts.URLMap) != 2 {
+		t.Errorf("Expected 2 URL references, got %v", opts.URLMap)
+	}
+}
+
+func TestMediaURLResolution(t *testing.T) {
+	htmlStr := `<audio src="talk.mp3"></audio><iframe src="/embed/1"></iframe>`
+
+	result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{
+		WebsiteDomain: "https://example.com/blog/",
+		ResolveURLs:   true,
+	})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	for _, check := range []string{"![Audio](https://example.com/blog/talk.mp3)", "[Embed](https://example.com/embed/1)"} {
+		if !strings.Contains(result, check) {
+			t.Errorf("Expected %q in output:\n%s", check, result)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+func TestSuperscriptAndSubscript(t *testing.T) {
+	tests := []struct {
+		name  string
+		style semanticmd.ScriptStyle
+		html  string
+		want  string
+	}{
+		{"caret sup", "", `<p>x<sup>2</sup></p>`, "x^2^"},
+		{"caret sub", semanticmd.ScriptStyleCaret, `<p>H<sub>2</sub>O</p>`, "H~2~O"},
+		{"html sup", semanticmd.ScriptStyleHTML, `<p>x<sup>2</sup></p>`, "x<sup>2</sup>"},
+		{"html sub", semanticmd.ScriptStyleHTML, `<p>H<sub>2</sub>O</p>`, "H<sub>2</sub>O"},
+		{"unicode sup", semanticmd.ScriptStyleUnicode, `<p>x<sup>n+1</sup></p>`, "xⁿ⁺¹"},
+		{"unicode sub", semanticmd.ScriptStyleUnicode, `<p>H<sub>2</sub>O</p>`, "H₂O"},
+		{"unicode fallback", semanticmd.ScriptStyleUnicode, `<p>x<sup>abc</sup></p>`, "x^abc^"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &semanticmd.ConversionOptions{ScriptStyle: tt.style}
+			result, err := semanticmd.ConvertString(tt.html, opts)
+			if err != nil {
+				t.Fatalf("Conversion failed: %v", err)
+			}
+			if !strings.Contains(result, tt.want) {
+				t.Errorf("Expected %q, got: %s", tt.want, result)
+			}
+		})
+	}
+}
+
+func TestInvalidScriptStyle(t *testing.T) {
+	opts := &semanticmd.ConversionOptions{ScriptStyle: "bogus"}
+	if _, err := semanticmd.ConvertString("<sup>1</sup>", opts); err == nil {
+		t.Error("Expected error for invalid ScriptStyle")
+	}
+}
+
+func TestHorizontalRule(t *testing.T) {
+	html := `<h1>Before</h1><hr><h2>After</h2>`
+	result, err := semanticmd.ConvertString(html, nil)
+	if err != nil {
+		t.Fatalf("Conversion failed: %v", err)
+	}
+
+	expected := "# Before\n\n---\n\n## After"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestHorizontalRuleAfterText(t *testing.T) {
+	tests := []struct {
+		html     string
+		expected string
+	}{
+		{`<p>Para</p><hr><p>x</p>`, "Para\n\n---\n\nx"},
+		{`<div>a<br><hr>b</div>`, "a\n\n---\n\nb"},
+		{`<p>Intro</p><h2>Title</h2>`, "Intro\n\n## Title"},
+		{`<ul><li>a<hr>b</li></ul>`, "- a\n\n  ---\n\n  b"},
+	}
+
+	for _, tt := range tests {
+		result, err := semanticmd.ConvertString(tt.html, nil)
+		if err != nil {
+			t.Fatalf("Conversion failed: %v", err)
+		}
+		if result != tt.expected {
+			t.Errorf("For %s expected %q, got %q", tt.html, tt.expected, result)
+		}
+	}
+}
@@ -137,3 +137,169 @@ func TestSemanticHTMLFooter(t *testing.T) {
 		t.Errorf("Footer should have opening HTML comment:\n%s", result)
 	}
 }
+
+func TestSemanticPresets(t *testing.T) {
+	htmlStr := `<nav><a href="/">Home</a></nav><article><p>Body</p></article><aside><p>Note</p></aside>`
+
+	tests := []struct {
+		preset   semanticmd.SemanticPreset
+		expected string
+	}{
+		{
+			preset:   semanticmd.SemanticPresetDefault,
+			expected: "<!-- <nav> -->\n[Home](/)\n<!-- </nav> -->\n\nBody\n\n<!-- <aside> -->\nNote\n<!-- </aside> -->",
+		},
+		{
+			preset:   semanticmd.SemanticPresetMinimal,
+			expected: "Body\n\nNote",
+		},
+		{
+			preset:   semanticmd.SemanticPresetStructure,
+			expected: "<!-- <nav> -->\n[Home](/)\n<!-- </nav> -->\n\n<!-- <article> -->\nBody\n<!-- </article> -->\n\n> Note",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.preset), func(t *testing.T) {
+			result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{SemanticPreset: tt.preset})
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSemanticElementPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		policy   semanticmd.SemanticPolicy
+		expected string
+	}{
+		{
+			name:     "drop",
+			html:     `<aside>Ad</aside><p>Body</p>`,
+			policy:   semanticmd.SemanticDrop,
+			expected: "Body",
+		},
+		{
+			name:     "rule",
+			html:     `<aside>Note</aside>`,
+			policy:   semanticmd.SemanticRule,
+			expected: "---\n\nNote\n\n---",
+		},
+		{
+			name:     "heading with label",
+			html:     `<aside aria-label="Related posts"><p>Post</p></aside>`,
+			policy:   semanticmd.SemanticHeading,
+			expected: "## Related posts\n\nPost",
+		},
+		{
+			name:     "heading from tag name",
+			html:     `<aside><p>Post</p></aside>`,
+			policy:   semanticmd.SemanticHeading,
+			expected: "## Aside\n\nPost",
+		},
+		{
+			name:     "blockquote",
+			html:     `<aside><p>Note</p></aside>`,
+			policy:   semanticmd.SemanticBlockquote,
+			expected: "> Note",
+		},
+		{
+			name:     "custom template",
+			html:     `<aside title="Tip"><p>Note</p></aside>`,
+			policy:   ":::{tag} {label}\n{content}\n:::",
+			expected: ":::aside Tip\nNote\n:::",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, &semanticmd.ConversionOptions{
+				SemanticElements: map[string]semanticmd.SemanticPolicy{"aside": tt.policy},
+			})
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if strings.TrimSpace(result) != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSemanticUnwrapInlineElements(t *testing.T) {
+	result, err := semanticmd.ConvertString(`<p>Updated <time>May 1</time></p>`, &semanticmd.ConversionOptions{
+		SemanticPreset: semanticmd.SemanticPresetMinimal,
+	})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	if !strings.Contains(result, "May 1") || strings.Contains(result, "May 1\n\n") {
+		t.Errorf("Expected time to stay inline:\n%q", result)
+	}
+}
+
+func TestInvalidSemanticOptions(t *testing.T) {
+	if _, err := semanticmd.ConvertString("<nav></nav>", &semanticmd.ConversionOptions{SemanticPreset: "compact"}); err == nil {
+		t.Error("Expected error for invalid SemanticPreset")
+	}
+	_, err := semanticmd.ConvertString("<nav></nav>", &semanticmd.ConversionOptions{
+		SemanticElements: map[string]semanticmd.SemanticPolicy{"nav": "hide"},
+	})
+	if err == nil {
+		t.Error("Expected error for invalid SemanticElements policy")
+	}
+	_, err = semanticmd.ConvertString("<details></details>", &semanticmd.ConversionOptions{
+		SemanticElements: map[string]semanticmd.SemanticPolicy{"details": semanticmd.SemanticDrop},
+	})
+	if err == nil {
+		t.Error("Expected error for a SemanticElements tag that is not a semantic element")
+	}
+}
+
+func TestSemanticElementsCaseInsensitive(t *testing.T) {
+	elements := map[string]semanticmd.SemanticPolicy{"NAV": semanticmd.SemanticDrop}
+	result, err := semanticmd.ConvertString("<nav>Menu</nav><p>Body</p>", &semanticmd.ConversionOptions{SemanticElements: elements})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	if result != "Body" {
+		t.Errorf("Expected nav to be dropped, got %q", result)
+	}
+	if _, ok := elements["nav"]; ok {
+		t.Error("Expected the caller's map to be left unchanged")
+	}
+}
+
+func TestInlineSemanticInParagraph(t *testing.T) {
+	htmlStr := `<p>two <mark>hi</mark> three</p>`
+
+	tests := []struct {
+		name     string
+		opts     *semanticmd.ConversionOptions
+		expected string
+	}{
+		{"structure", &semanticmd.ConversionOptions{SemanticPreset: semanticmd.SemanticPresetStructure}, "two hi three"},
+		{"minimal", &semanticmd.ConversionOptions{SemanticPreset: semanticmd.SemanticPresetMinimal}, "two hi three"},
+		{"template", &semanticmd.ConversionOptions{
+			SemanticElements: map[string]semanticmd.SemanticPolicy{"mark": "=={content}=="},
+		}, "two ==hi== three"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(htmlStr, tt.opts)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
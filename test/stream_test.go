@@ -0,0 +1,142 @@
+package semanticmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+)
+
+// recordingWriter records every write it receives.
+type recordingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestConvertToMatchesConvertString(t *testing.T) {
+	inputs, err := filepath.Glob("../testdata/*/*/*.html")
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("Failed to find fixtures: %v", err)
+	}
+
+	for _, inputFile := range inputs {
+		t.Run(filepath.Base(inputFile), func(t *testing.T) {
+			input, err := os.ReadFile(inputFile)
+			if err != nil {
+				t.Fatalf("Failed to read input file: %v", err)
+			}
+
+			opts := &semanticmd.ConversionOptions{IncludeMetaData: semanticmd.MetaDataExtended}
+			expected, err := semanticmd.ConvertString(string(input), opts)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := semanticmd.ConvertTo(&buf, bytes.NewReader(input), opts); err != nil {
+				t.Fatalf("ConvertTo failed: %v", err)
+			}
+
+			if buf.String() != expected {
+				t.Errorf("Streamed output differs\n\nExpected:\n%s\n\nActual:\n%s", expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestConvertToStreamsBlocks(t *testing.T) {
+	htmlStr := `<h1>Title</h1><p>* not a list</p><h2>Next</h2><ul><li>Item</li></ul><p>1. not ordered</p>`
+
+	expected, err := semanticmd.ConvertString(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	var w recordingWriter
+	if err := semanticmd.ConvertTo(&w, strings.NewReader(htmlStr), nil); err != nil {
+		t.Fatalf("ConvertTo failed: %v", err)
+	}
+
+	if w.String() != expected {
+		t.Errorf("Streamed output differs\n\nExpected:\n%s\n\nActual:\n%s", expected, w.String())
+	}
+	if w.writes < 3 {
+		t.Errorf("Expected output to be written in several blocks, got %d writes", w.writes)
+	}
+}
+
+func TestConvertToStreamsWrappedDocument(t *testing.T) {
+	htmlStr := `<main><article><h1>Title</h1><p>First</p><section><h2>Next</h2><p>Second</p></section><p>Third</p></article></main>`
+
+	for _, preset := range []semanticmd.SemanticPreset{semanticmd.SemanticPresetDefault, semanticmd.SemanticPresetStructure} {
+		t.Run(string(preset), func(t *testing.T) {
+			opts := &semanticmd.ConversionOptions{SemanticPreset: preset}
+			expected, err := semanticmd.ConvertString(htmlStr, opts)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+
+			var w recordingWriter
+			if err := semanticmd.ConvertTo(&w, strings.NewReader(htmlStr), opts); err != nil {
+				t.Fatalf("ConvertTo failed: %v", err)
+			}
+
+			if w.String() != expected {
+				t.Errorf("Streamed output differs\n\nExpected:\n%s\n\nActual:\n%s", expected, w.String())
+			}
+			if w.writes < 3 {
+				t.Errorf("Expected the wrapped document to be written in several blocks, got %d writes", w.writes)
+			}
+		})
+	}
+}
+
+// cancelingWriter cancels a context on its first write.
+type cancelingWriter struct {
+	bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (w *cancelingWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return w.Buffer.Write(p)
+}
+
+func TestConvertToCancelsWrappedDocument(t *testing.T) {
+	htmlStr := "<main>" + strings.Repeat("<h2>Heading</h2><p>Text</p>", 100) + "</main>"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := &cancelingWriter{cancel: cancel}
+
+	err := semanticmd.ConvertToContext(ctx, w, strings.NewReader(htmlStr), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if strings.Count(w.String(), "Heading") > 1 {
+		t.Errorf("Expected rendering to stop after the first block, got:\n%s", w.String())
+	}
+}
+
+func TestConvertToOutputLimit(t *testing.T) {
+	htmlStr := strings.Repeat("<h2>Heading</h2>", 100)
+
+	var buf bytes.Buffer
+	err := semanticmd.ConvertTo(&buf, strings.NewReader(htmlStr), &semanticmd.ConversionOptions{MaxOutputBytes: 50})
+	if !errors.Is(err, semanticmd.ErrOutputTooLarge) {
+		t.Errorf("Expected ErrOutputTooLarge, got %v", err)
+	}
+	if buf.Len() > 50 {
+		t.Errorf("Expected at most 50 bytes written, got %d", buf.Len())
+	}
+}
@@ -1,6 +1,7 @@
 package semanticmd_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -261,3 +262,406 @@ func TestComplexTableDocument(t *testing.T) {
 		t.Error("Expected table content not found")
 	}
 }
+
+func TestTableHeaderlessGetsSyntheticHeader(t *testing.T) {
+	htmlStr := `<table><tr><td>A</td><td>B</td></tr><tr><td>C</td><td>D</td></tr></table>`
+
+	result, err := semanticmd.ConvertString(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	expected := "|  |  |\n| --- | --- |\n| A | B |\n| C | D |"
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestTableAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		html      string
+		separator string
+	}{
+		{
+			name:      "align attribute",
+			html:      `<table><tr><th align="left">L</th><th align="center">C</th><th align="right">R</th><th>D</th></tr></table>`,
+			separator: "| :--- | :---: | ---: | --- |",
+		},
+		{
+			name:      "text-align style",
+			html:      `<table><tr><th>Item</th><th style="color: red; text-align: right">Amount</th></tr></table>`,
+			separator: "| --- | ---: |",
+		},
+		{
+			name:      "body cells",
+			html:      `<table><tr><th>Item</th><th>Amount</th></tr><tr><td>A</td><td align="right">1.00</td></tr><tr><td>B</td><td align="right">2.00</td></tr></table>`,
+			separator: "| --- | ---: |",
+		},
+		{
+			name:      "col elements",
+			html:      `<table><colgroup><col><col span="2" align="right"></colgroup><tr><th>Item</th><th>Q1</th><th>Q2</th></tr></table>`,
+			separator: "| --- | ---: | ---: |",
+		},
+		{
+			name:      "row alignment",
+			html:      `<table><thead align="center"><tr><th>A</th><th>B</th></tr></thead></table>`,
+			separator: "| :---: | :---: |",
+		},
+		{
+			name: "rowspan shifts cells",
+			html: `<table><tr><th>Region</th><th>Quarter</th><th>Sales</th></tr>` +
+				`<tr><td rowspan="2">North</td><td>Q1</td><td align="right">10</td></tr>` +
+				`<tr><td>Q2</td><td align="right">20</td></tr></table>`,
+			separator: "| --- | --- | ---: |",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := semanticmd.ConvertString(tt.html, nil)
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if !strings.Contains(result, tt.separator) {
+				t.Errorf("Expected separator %q, got:\n%s", tt.separator, result)
+			}
+		})
+	}
+}
+
+func TestTableSpanModes(t *testing.T) {
+	htmlStr := `
+	<table>
+		<tr><th>Region</th><th>Quarter</th><th>Sales</th></tr>
+		<tr><td rowspan="2">North</td><td>Q1</td><td>10</td></tr>
+		<tr><td>Q2</td><td>20</td></tr>
+		<tr><td colspan="2">Total</td><td>30</td></tr>
+	</table>
+	`
+
+	tests := []struct {
+		mode     semanticmd.TableSpanMode
+		expected string
+	}{
+		{semanticmd.TableSpanDuplicate, `| Region | Quarter | Sales |
+| --- | --- | --- |
+| North | Q1 | 10 |
+| North | Q2 | 20 |
+| Total | Total | 30 |`},
+		{semanticmd.TableSpanEmpty, `| Region | Quarter | Sales |
+| --- | --- | --- |
+| North | Q1 | 10 |
+|  | Q2 | 20 |
+| Total |  | 30 |`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableSpans: tt.mode})
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTableColumnTrackingUsesGridColumn(t *testing.T) {
+	htmlStr := `
+	<table>
+		<tr><td rowspan="2">A1</td><td>B1</td><td>C1</td></tr>
+		<tr><td>B2</td><td>C2</td></tr>
+	</table>
+	`
+
+	opts := &semanticmd.ConversionOptions{EnableTableColumnTracking: true}
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	// The second row starts in column B, below the rowspan
+	if !strings.Contains(result, "| B2 <!-- B --> | C2 <!-- C --> |") {
+		t.Errorf("Expected column IDs from the grid column, got:\n%s", result)
+	}
+}
+
+func TestTableHugeSpansAreClamped(t *testing.T) {
+	htmlStr := `<table><tr><td colspan="2000000000" rowspan="2000000000">x</td></tr><tr><td>y</td></tr></table>`
+
+	for _, opts := range []*semanticmd.ConversionOptions{
+		nil,
+		{EnableTableColumnTracking: true},
+		{TableSpans: semanticmd.TableSpanEmpty},
+		{TableFormat: semanticmd.TableFormatHTML},
+	} {
+		result, err := semanticmd.ConvertString(htmlStr, opts)
+		if err != nil {
+			t.Fatalf("ConvertString failed: %v", err)
+		}
+		if strings.Contains(result, "2000000000") {
+			t.Errorf("Expected spans clamped to the HTML limits, got:\n%.200s", result)
+		}
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	if !strings.Contains(result, "| x <!-- colspan: 1000 --> <!-- rowspan: 65534 --> |") {
+		t.Errorf("Expected clamped span annotations, got:\n%s", result)
+	}
+}
+
+func TestTableSpanModeSpannedCells(t *testing.T) {
+	htmlStr := `<table><tr><td colspan="2"><a href="/x">Wide</a></td></tr></table>`
+
+	nodes, err := semanticmd.Parse(htmlStr, &semanticmd.ConversionOptions{TableSpans: semanticmd.TableSpanDuplicate})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	table := nodes[0].(*semanticmd.TableNode)
+	cells := table.Rows[0].Cells
+	if len(cells) != 2 {
+		t.Fatalf("Expected 2 cells, got %d", len(cells))
+	}
+	if cells[0].Spanned || !cells[1].Spanned {
+		t.Errorf("Expected only the second cell to be marked as spanned")
+	}
+	// Duplicated content must not share nodes with the original
+	if cells[0].Content[0] == cells[1].Content[0] {
+		t.Error("Expected duplicated cell content to be a separate copy")
+	}
+}
+
+func TestInvalidTableSpanMode(t *testing.T) {
+	_, err := semanticmd.ConvertString("<table></table>", &semanticmd.ConversionOptions{TableSpans: "merge"})
+	if err == nil {
+		t.Error("Expected error for invalid TableSpans")
+	}
+}
+
+func TestTableFormats(t *testing.T) {
+	htmlStr := `<table><tr><th>Name</th><th align="right">Age</th></tr><tr><td>Alice</td><td>30</td></tr><tr><td>Bob, Jr.</td><td></td></tr></table>`
+
+	tests := []struct {
+		format   semanticmd.TableFormat
+		expected string
+	}{
+		{semanticmd.TableFormatHTML, "<table>\n<tr><th>Name</th><th align=\"right\">Age</th></tr>\n<tr><td>Alice</td><td>30</td></tr>\n<tr><td>Bob, Jr.</td><td></td></tr>\n</table>"},
+		{semanticmd.TableFormatRecords, "- Name: Alice\n  Age: 30\n\n- Name: Bob, Jr."},
+		{semanticmd.TableFormatCSV, "```csv\nName,Age\nAlice,30\n\"Bob, Jr.\",\n```"},
+		{semanticmd.TableFormatJSON, "```json\n[\n  {\"Name\": \"Alice\", \"Age\": \"30\"},\n  {\"Name\": \"Bob, Jr.\", \"Age\": \"\"}\n]\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: tt.format})
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTableFormatsWithSpans(t *testing.T) {
+	htmlStr := `<table><tr><th>Name</th><th colspan="2">Scores</th><th>Total</th></tr>` +
+		`<tr><td rowspan="2">Ann</td><td>1</td><td>2</td><td>3</td></tr>` +
+		`<tr><td>4</td><td>5</td><td>9</td></tr></table>`
+
+	tests := []struct {
+		format   semanticmd.TableFormat
+		expected string
+	}{
+		{semanticmd.TableFormatRecords, "- Name: Ann\n  Scores: 1\n  Scores 2: 2\n  Total: 3\n\n- Name: Ann\n  Scores: 4\n  Scores 2: 5\n  Total: 9"},
+		{semanticmd.TableFormatCSV, "```csv\nName,Scores,Scores,Total\nAnn,1,2,3\nAnn,4,5,9\n```"},
+		{semanticmd.TableFormatJSON, "```json\n[\n  {\"Name\": \"Ann\", \"Scores\": \"1\", \"Scores 2\": \"2\", \"Total\": \"3\"},\n  {\"Name\": \"Ann\", \"Scores\": \"4\", \"Scores 2\": \"5\", \"Total\": \"9\"}\n]\n```"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: tt.format})
+			if err != nil {
+				t.Fatalf("ConvertString failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\n\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: semanticmd.TableFormatAuto})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	if !strings.HasPrefix(result, "<table>") {
+		t.Errorf("Expected spans to make auto use HTML, got:\n%s", result)
+	}
+}
+
+func TestTableFormatJSONWithoutHeader(t *testing.T) {
+	htmlStr := `<table><tr><td>a</td><td>b</td></tr></table>`
+
+	result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: semanticmd.TableFormatJSON})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	expected := "```json\n[\n  [\"a\", \"b\"]\n]\n```"
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestTableFormatAuto(t *testing.T) {
+	opts := &semanticmd.ConversionOptions{TableFormat: semanticmd.TableFormatAuto}
+
+	t.Run("simple table stays pipe", func(t *testing.T) {
+		result, err := semanticmd.ConvertString(`<table><tr><th>A</th></tr><tr><td>1</td></tr></table>`, opts)
+		if err != nil {
+			t.Fatalf("ConvertString failed: %v", err)
+		}
+		if !strings.HasPrefix(result, "| A |") {
+			t.Errorf("Expected pipe table, got:\n%s", result)
+		}
+	})
+
+	t.Run("block content uses html", func(t *testing.T) {
+		result, err := semanticmd.ConvertString(`<table><tr><th>A</th></tr><tr><td><ul><li>x</li></ul></td></tr></table>`, opts)
+		if err != nil {
+			t.Fatalf("ConvertString failed: %v", err)
+		}
+		if !strings.HasPrefix(result, "<table>") {
+			t.Errorf("Expected HTML table, got:\n%s", result)
+		}
+	})
+
+	t.Run("nested table uses html", func(t *testing.T) {
+		result, err := semanticmd.ConvertString(`<table><tr><td><table><tr><td>inner</td></tr></table></td></tr></table>`, opts)
+		if err != nil {
+			t.Fatalf("ConvertString failed: %v", err)
+		}
+		if !strings.HasPrefix(result, "<table>") || !strings.Contains(result, "inner") {
+			t.Errorf("Expected HTML table, got:\n%s", result)
+		}
+	})
+
+	t.Run("wide table uses records", func(t *testing.T) {
+		var header, row strings.Builder
+		for i := 0; i < 10; i++ {
+			header.WriteString(fmt.Sprintf("<th>H%d</th>", i))
+			row.WriteString(fmt.Sprintf("<td>v%d</td>", i))
+		}
+		htmlStr := "<table><tr>" + header.String() + "</tr><tr>" + row.String() + "</tr></table>"
+
+		result, err := semanticmd.ConvertString(htmlStr, opts)
+		if err != nil {
+			t.Fatalf("ConvertString failed: %v", err)
+		}
+		if !strings.HasPrefix(result, "- H0: v0\n  H1: v1") {
+			t.Errorf("Expected records, got:\n%s", result)
+		}
+	})
+}
+
+func TestTableFormatHTMLBlockCells(t *testing.T) {
+	htmlStr := `<table><tr><td><h3>Intro</h3><pre><code>a
+
+b</code></pre><ul><li>x</li></ul></td></tr></table>`
+
+	result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: semanticmd.TableFormatHTML})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	// Cell Markdown is set off by blank lines and code keeps its blank line
+	expected := "<table>\n<tr><td>\n\n### Intro\n\n```\na\n\nb\n```\n\n- x\n\n</td></tr>\n</table>"
+	if result != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestInvalidTableFormat(t *testing.T) {
+	_, err := semanticmd.ConvertString("<table></table>", &semanticmd.ConversionOptions{TableFormat: "xml"})
+	if err == nil {
+		t.Error("Expected error for invalid TableFormat")
+	}
+}
+
+func TestTableCaptionAndFooter(t *testing.T) {
+	htmlStr := `<table>
+		<caption>Sales</caption>
+		<tfoot><tr><td>Total</td><td>30</td></tr></tfoot>
+		<thead><tr><th>Region</th><th>Units</th></tr></thead>
+		<tbody><tr><td>North</td><td>10</td></tr><tr><td>South</td><td>20</td></tr></tbody>
+	</table>`
+
+	result, err := semanticmd.ConvertString(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	expected := "**Sales**\n\n| Region | Units |\n| --- | --- |\n| North | 10 |\n| South | 20 |\n| Total | 30 |"
+	if strings.TrimSpace(result) != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+
+	result, err = semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: semanticmd.TableFormatHTML})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	for _, want := range []string{"<caption>Sales</caption>", "<tfoot>\n<tr><td>Total</td><td>30</td></tr>\n</tfoot>"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Expected %q in HTML table, got:\n%s", want, result)
+		}
+	}
+}
+
+func TestTableRowHeaders(t *testing.T) {
+	htmlStr := `<table>
+		<tr><th>Region</th><th>Units</th></tr>
+		<tr><th>North</th><td>10</td></tr>
+		<tr><th scope="row">South</th><td>20</td></tr>
+	</table>`
+
+	result, err := semanticmd.ConvertString(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	expected := "| Region | Units |\n| --- | --- |\n| **North** | 10 |\n| **South** | 20 |"
+	if strings.TrimSpace(result) != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+
+	result, err = semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: semanticmd.TableFormatRecords})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	expected = "- **North**\n  Units: 10\n\n- **South**\n  Units: 20"
+	if strings.TrimSpace(result) != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
+
+func TestTableHeadersAttribute(t *testing.T) {
+	htmlStr := `<table>
+		<tr><td></td><th id="q1">Q1</th><th id="q2">Q2</th></tr>
+		<tr><th id="rev">Revenue</th><td headers="rev q1">5</td><td headers="rev q2">7</td></tr>
+	</table>`
+
+	result, err := semanticmd.ConvertString(htmlStr, &semanticmd.ConversionOptions{TableFormat: semanticmd.TableFormatRecords})
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+	expected := "- **Revenue**\n  Revenue / Q1: 5\n  Revenue / Q2: 7"
+	if strings.TrimSpace(result) != expected {
+		t.Errorf("Expected:\n%s\n\nGot:\n%s", expected, result)
+	}
+}
@@ -163,3 +163,115 @@ func TestCombinedFeatures(t *testing.T) {
 		t.Error("Image URL should be refified")
 	}
 }
+
+func TestURLResolutionWithDomain(t *testing.T) {
+	htmlStr := `
+	<html>
+	<body>
+		<a href="/docs/intro">Intro</a>
+		<a href="guide.html">Guide</a>
+		<a href="#top">Top</a>
+		<img src="../images/photo.jpg" alt="Photo">
+		<video src="//cdn.example.com/clip.mp4" poster="poster.jpg"></video>
+		<a href="mailto:team@example.com">Mail</a>
+	</body>
+	</html>
+	`
+
+	opts := &semanticmd.ConversionOptions{
+		WebsiteDomain: "example.com/blog/",
+		ResolveURLs:   true,
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	checks := []string{
+		"[Intro](https://example.com/docs/intro)",
+		"[Guide](https://example.com/blog/guide.html)",
+		"[Top](#top)",
+		"![Photo](https://example.com/images/photo.jpg)",
+		"![Video](https://cdn.example.com/clip.mp4)",
+		"![Poster](https://example.com/blog/poster.jpg)",
+		"[Mail](mailto:team@example.com)",
+	}
+	for _, check := range checks {
+		if !strings.Contains(result, check) {
+			t.Errorf("Expected %q in output:\n%s", check, result)
+		}
+	}
+}
+
+func TestURLResolutionBaseHref(t *testing.T) {
+	htmlStr := `
+	<html>
+	<head><base href="/static/"></head>
+	<body>
+		<a href="page.html">Page</a>
+	</body>
+	</html>
+	`
+
+	opts := &semanticmd.ConversionOptions{
+		WebsiteDomain: "https://example.com/ignored/",
+		ResolveURLs:   true,
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	if !strings.Contains(result, "[Page](https://example.com/static/page.html)") {
+		t.Errorf("Expected URL resolved against <base href>:\n%s", result)
+	}
+}
+
+func TestURLResolutionDisabledByDefault(t *testing.T) {
+	htmlStr := `<a href="/local/path">Local</a>`
+
+	opts := &semanticmd.ConversionOptions{
+		WebsiteDomain: "https://example.com",
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	if !strings.Contains(result, "[Local](/local/path)") {
+		t.Errorf("Relative URL should be preserved without ResolveURLs:\n%s", result)
+	}
+}
+
+func TestURLResolutionWithRefification(t *testing.T) {
+	htmlStr := `
+	<html>
+	<body>
+		<img src="/images/photos/hero.jpg" alt="Hero">
+		<img src="/images/photos/gallery.jpg" alt="Gallery">
+	</body>
+	</html>
+	`
+
+	opts := &semanticmd.ConversionOptions{
+		WebsiteDomain:   "https://example.com",
+		ResolveURLs:     true,
+		RefifyURLs:      true,
+		IncludeMetaData: semanticmd.MetaDataBasic,
+	}
+
+	result, err := semanticmd.ConvertString(htmlStr, opts)
+	if err != nil {
+		t.Fatalf("ConvertString failed: %v", err)
+	}
+
+	if !strings.Contains(result, "ref0: https://example.com/images/photos") {
+		t.Errorf("Expected resolved URL prefix in reference legend:\n%s", result)
+	}
+	if !strings.Contains(result, "![Hero](ref0://hero.jpg)") {
+		t.Errorf("Expected resolved image URL to be refified:\n%s", result)
+	}
+}
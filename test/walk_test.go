@@ -0,0 +1,162 @@
+package semanticmd_test
+
+import (
+	"strings"
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
+
+const walkHTML = `
+<h1>Title <a href="/h">here</a></h1>
+<ul><li><a href="/item">Item</a></li></ul>
+<table><tr><td><a href="/cell">Cell</a></td></tr></table>
+<blockquote><p><strong><a href="/quote">Quote</a></strong></p></blockquote>
+<section><img src="/img.png" alt="Img"></section>
+`
+
+func TestInspectVisitsAllNodes(t *testing.T) {
+	nodes, err := semanticmd.Parse(walkHTML, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var hrefs []string
+	counts := make(map[string]int)
+	types.Inspect(nodes, func(node types.Node) bool {
+		counts[node.Type()]++
+		if link, ok := node.(*types.LinkNode); ok {
+			hrefs = append(hrefs, link.Href)
+		}
+		return true
+	})
+
+	if got := strings.Join(hrefs, ","); got != "/h,/item,/cell,/quote" {
+		t.Errorf("Expected links in document order, got %s", got)
+	}
+	for _, typ := range []string{"listItem", "tableRow", "tableCell", "image", "bold"} {
+		if counts[typ] != 1 {
+			t.Errorf("Expected one %s node, got %d", typ, counts[typ])
+		}
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	nodes, err := semanticmd.Parse(walkHTML, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	links := 0
+	types.Inspect(nodes, func(node types.Node) bool {
+		if _, ok := node.(*types.TableNode); ok {
+			return false
+		}
+		if _, ok := node.(*types.LinkNode); ok {
+			links++
+		}
+		return true
+	})
+
+	if links != 3 {
+		t.Errorf("Expected table links to be skipped (3 links), got %d", links)
+	}
+}
+
+type depthVisitor struct {
+	depth    int
+	maxDepth *int
+}
+
+func (v depthVisitor) Visit(node types.Node) types.Visitor {
+	if node == nil {
+		return nil
+	}
+	if v.depth > *v.maxDepth {
+		*v.maxDepth = v.depth
+	}
+	return depthVisitor{depth: v.depth + 1, maxDepth: v.maxDepth}
+}
+
+func TestWalkVisitor(t *testing.T) {
+	nodes, err := semanticmd.Parse(`<table><tr><td><b>deep</b></td></tr></table>`, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	maxDepth := 0
+	types.Walk(depthVisitor{maxDepth: &maxDepth}, nodes[0])
+
+	// table > row > cell > bold > text
+	if maxDepth != 4 {
+		t.Errorf("Expected max depth 4, got %d", maxDepth)
+	}
+}
+
+func TestRewriteReplaceAndRemove(t *testing.T) {
+	nodes, err := semanticmd.Parse(walkHTML, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	nodes = types.Rewrite(nodes, func(node types.Node) []types.Node {
+		switch n := node.(type) {
+		case *types.ImageNode:
+			return nil
+		case *types.LinkNode:
+			if n.Href == "/cell" {
+				return n.Content
+			}
+		}
+		return []types.Node{node}
+	})
+
+	result, err := semanticmd.Render(nodes, nil)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	if strings.Contains(result, "![Img]") {
+		t.Errorf("Expected image to be removed, got:\n%s", result)
+	}
+	if strings.Contains(result, "(/cell)") || !strings.Contains(result, "| Cell |") {
+		t.Errorf("Expected table link to be unwrapped, got:\n%s", result)
+	}
+	if !strings.Contains(result, "[Quote](/quote)") {
+		t.Errorf("Expected other links to be kept, got:\n%s", result)
+	}
+}
+
+func TestRewriteVisitsSameNodesAsInspect(t *testing.T) {
+	nodes, err := semanticmd.Parse(walkHTML+`
+<table><caption><a href="/caption">Caption</a></caption><tfoot><tr><td><a href="/foot">Foot</a></td></tr></tfoot></table>
+<dl><dt><a href="/term">Term</a></dt><dd>Definition</dd></dl>
+<figure><img src="/fig.png" alt="Fig"><figcaption><a href="/figcaption">Caption</a></figcaption></figure>
+<details><summary><a href="/summary">Summary</a></summary><p><a href="/details">Details</a></p></details>
+`, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var inspected, rewritten []string
+	types.Inspect(nodes, func(node types.Node) bool {
+		if link, ok := node.(*types.LinkNode); ok {
+			inspected = append(inspected, link.Href)
+		}
+		return true
+	})
+	types.Rewrite(nodes, func(node types.Node) []types.Node {
+		if link, ok := node.(*types.LinkNode); ok {
+			rewritten = append(rewritten, link.Href)
+		}
+		return []types.Node{node}
+	})
+
+	if len(inspected) != 10 {
+		t.Errorf("Expected 10 links, got %v", inspected)
+	}
+	if got, want := strings.Join(rewritten, ","), strings.Join(inspected, ","); got != want {
+		t.Errorf("Expected Rewrite to reach the links Inspect visits\n\nInspect: %s\nRewrite: %s", want, got)
+	}
+}
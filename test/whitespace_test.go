@@ -0,0 +1,40 @@
+package semanticmd_test
+
+import (
+	"testing"
+
+	semanticmd "github.com/thorstenpfister/semantic-markdown"
+	"github.com/thorstenpfister/semantic-markdown/types"
+)
+
+func TestWhitespaceTextNodes(t *testing.T) {
+	htmlStr := `<p> Price:&nbsp;<b>10 </b> EUR <span style="white-space: pre">  a  b </span></p>`
+
+	nodes, err := semanticmd.Parse(htmlStr, nil)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var texts []*semanticmd.TextNode
+	types.Inspect(nodes, func(node types.Node) bool {
+		if text, ok := node.(*semanticmd.TextNode); ok {
+			texts = append(texts, text)
+		}
+		return true
+	})
+
+	expected := []semanticmd.TextNode{
+		{Content: "Price: "},
+		{Content: "10 "},
+		{Content: "EUR "},
+		{Content: "  a  b ", Preformatted: true},
+	}
+	if len(texts) != len(expected) {
+		t.Fatalf("Expected %d text nodes, got %d: %+v", len(expected), len(texts), texts)
+	}
+	for i, text := range texts {
+		if *text != expected[i] {
+			t.Errorf("Text node %d: expected %+v, got %+v", i, expected[i], *text)
+		}
+	}
+}
@@ -6,6 +6,11 @@ import "golang.org/x/net/html"
 // Return non-nil nodes to override default processing, nil to use default.
 type ElementProcessor func(element *html.Node, opts *ConversionOptions, indentLevel int) []Node
 
+// CodeLanguageDetector determines the language of a code block from its
+// <pre> element and text content.
+// Return a language to override detection, empty to use the built-in detection.
+type CodeLanguageDetector func(pre *html.Node, code string) string
+
 // NodeRenderer renders an AST node to markdown string.
 // Return non-empty string to override default rendering, empty to use default.
 type NodeRenderer func(node Node, opts *ConversionOptions, indentLevel int) string
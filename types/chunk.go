@@ -0,0 +1,34 @@
+package types
+
+// DefaultChunkSize is the chunk budget used when ChunkOptions.MaxSize is unset.
+const DefaultChunkSize = 2000
+
+// ChunkOptions configures splitting converted Markdown into chunks.
+type ChunkOptions struct {
+	// MaxSize is the size budget per chunk, measured by SizeFunc.
+	// Defaults to DefaultChunkSize. A single block that exceeds the budget
+	// on its own (e.g. a large table or code block) is emitted as its own chunk.
+	MaxSize int
+
+	// SizeFunc measures the size of rendered Markdown, e.g. a token counter.
+	// Defaults to counting runes.
+	SizeFunc func(markdown string) int
+
+	// IncludeFrontmatter prepends the YAML frontmatter to every chunk's Content.
+	// The frontmatter size counts against MaxSize.
+	IncludeFrontmatter bool
+}
+
+// Chunk is a self-contained piece of the converted document.
+type Chunk struct {
+	// Content is the rendered Markdown of the chunk.
+	Content string
+
+	// Headings is the breadcrumb of heading texts enclosing the chunk,
+	// outermost first.
+	Headings []string
+
+	// Metadata is the page metadata, shared by all chunks of a document.
+	// Nil unless IncludeMetaData is set.
+	Metadata *MetaDataNode
+}
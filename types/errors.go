@@ -0,0 +1,12 @@
+package types
+
+import "errors"
+
+// Limit errors returned when a conversion exceeds a configured limit.
+// Errors are wrapped with details; use errors.Is to check for them.
+var (
+	ErrInputTooLarge    = errors.New("input exceeds MaxInputBytes")
+	ErrMaxDepthExceeded = errors.New("document nesting exceeds MaxDepth")
+	ErrMaxNodesExceeded = errors.New("document exceeds MaxNodes")
+	ErrOutputTooLarge   = errors.New("output exceeds MaxOutputBytes")
+)
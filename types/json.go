@@ -0,0 +1,365 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON serialization of the AST.
+//
+// Every node marshals to a JSON object with a "type" discriminator holding
+// the value of Type(), followed by the node's fields:
+//
+//	{"type":"heading","level":1,"content":[{"type":"text","content":"Title"}]}
+//
+// Decode nodes with UnmarshalNode or UnmarshalNodes, which pick the concrete
+// type from the discriminator. null elements in node lists are skipped.
+// CustomNode content round-trips as generic JSON values (maps, slices, ...).
+
+// nodeFactories maps type discriminators to constructors for UnmarshalNode.
+var nodeFactories = map[string]func() Node{
+	"text":              func() Node { return &TextNode{} },
+	"bold":              func() Node { return &BoldNode{} },
+	"italic":            func() Node { return &ItalicNode{} },
+	"strikethrough":     func() Node { return &StrikethroughNode{} },
+	"superscript":       func() Node { return &SuperscriptNode{} },
+	"subscript":         func() Node { return &SubscriptNode{} },
+	"heading":           func() Node { return &HeadingNode{} },
+	"link":              func() Node { return &LinkNode{} },
+	"image":             func() Node { return &ImageNode{} },
+	"video":             func() Node { return &VideoNode{} },
+	"audio":             func() Node { return &AudioNode{} },
+	"embed":             func() Node { return &EmbedNode{} },
+	"figure":            func() Node { return &FigureNode{} },
+	"details":           func() Node { return &DetailsNode{} },
+	"list":              func() Node { return &ListNode{} },
+	"listItem":          func() Node { return &ListItemNode{} },
+	"definitionList":    func() Node { return &DefinitionListNode{} },
+	"definitionItem":    func() Node { return &DefinitionItemNode{} },
+	"table":             func() Node { return &TableNode{} },
+	"tableRow":          func() Node { return &TableRowNode{} },
+	"tableCell":         func() Node { return &TableCellNode{} },
+	"code":              func() Node { return &CodeNode{} },
+	"math":              func() Node { return &MathNode{} },
+	"thematicBreak":     func() Node { return &ThematicBreakNode{} },
+	"footnoteReference": func() Node { return &FootnoteReferenceNode{} },
+	"footnote":          func() Node { return &FootnoteNode{} },
+	"paragraph":         func() Node { return &ParagraphNode{} },
+	"blockquote":        func() Node { return &BlockquoteNode{} },
+	"semanticHtml":      func() Node { return &SemanticHTMLNode{} },
+	"meta":              func() Node { return &MetaDataNode{} },
+	"custom":            func() Node { return &CustomNode{} },
+}
+
+// nodeListFields returns the []Node fields of node by JSON name. Node
+// types embed their children as []Node, which encoding/json cannot decode
+// on its own.
+func nodeListFields(node Node) map[string]*[]Node {
+	switch n := node.(type) {
+	case *FigureNode:
+		return map[string]*[]Node{"content": &n.Content, "caption": &n.Caption}
+	case *DetailsNode:
+		return map[string]*[]Node{"summary": &n.Summary, "content": &n.Content}
+	case *TableNode:
+		return map[string]*[]Node{"caption": &n.Caption}
+	}
+	if content := contentOf(node); content != nil {
+		return map[string]*[]Node{"content": content}
+	}
+	return nil
+}
+
+// MarshalNodes encodes an AST as a JSON array.
+func MarshalNodes(nodes []Node) ([]byte, error) {
+	if nodes == nil {
+		nodes = []Node{}
+	}
+	return json.Marshal(nodes)
+}
+
+// UnmarshalNodes decodes a JSON array produced by MarshalNodes.
+func UnmarshalNodes(data []byte) ([]Node, error) {
+	var nodes nodeList
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+// UnmarshalNode decodes a single JSON node, using its "type" field to pick
+// the concrete node type.
+func UnmarshalNode(data []byte) (Node, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	if head.Type == "" {
+		return nil, fmt.Errorf("node has no type")
+	}
+	factory, ok := nodeFactories[head.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown node type: %q", head.Type)
+	}
+
+	node := factory()
+	if err := decodeNode(data, node, nodeListFields(node)); err != nil {
+		return nil, fmt.Errorf("failed to decode %s node: %w", head.Type, err)
+	}
+	return node, nil
+}
+
+// nodeList decodes a JSON array of polymorphic nodes.
+type nodeList []Node
+
+func (l *nodeList) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*l = nil
+		return nil
+	}
+
+	nodes := make([]Node, 0, len(raw))
+	for _, item := range raw {
+		// Walk and Rewrite skip nil children, so null elements are dropped
+		if bytes.Equal(bytes.TrimSpace(item), []byte("null")) {
+			continue
+		}
+		node, err := UnmarshalNode(item)
+		if err != nil {
+			return err
+		}
+		nodes = append(nodes, node)
+	}
+	*l = nodes
+	return nil
+}
+
+// decodeNode decodes data into v, a node or a method-less alias of one.
+// The fields are decoded as polymorphic node lists and the rest of the
+// object with encoding/json.
+func decodeNode(data []byte, v any, fields map[string]*[]Node) error {
+	if len(fields) == 0 {
+		return json.Unmarshal(data, v)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for name, slice := range fields {
+		msg, ok := raw[name]
+		if !ok {
+			continue
+		}
+		var nodes nodeList
+		if err := json.Unmarshal(msg, &nodes); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		*slice = nodes
+		delete(raw, name)
+	}
+
+	rest, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(rest, v)
+}
+
+// marshalTyped encodes v (a method-less alias of a node struct) and prepends
+// the "type" discriminator.
+func marshalTyped(typ string, v any) ([]byte, error) {
+	fields, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":`)
+	typJSON, _ := json.Marshal(typ)
+	buf.Write(typJSON)
+	if len(fields) > 2 { // not "{}"
+		buf.WriteByte(',')
+		buf.Write(fields[1:])
+	} else {
+		buf.WriteByte('}')
+	}
+	return buf.Bytes(), nil
+}
+
+func (n *TextNode) MarshalJSON() ([]byte, error) {
+	type plain TextNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *BoldNode) MarshalJSON() ([]byte, error) {
+	type plain BoldNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *ItalicNode) MarshalJSON() ([]byte, error) {
+	type plain ItalicNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *StrikethroughNode) MarshalJSON() ([]byte, error) {
+	type plain StrikethroughNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *SuperscriptNode) MarshalJSON() ([]byte, error) {
+	type plain SuperscriptNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *SubscriptNode) MarshalJSON() ([]byte, error) {
+	type plain SubscriptNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *HeadingNode) MarshalJSON() ([]byte, error) {
+	type plain HeadingNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *LinkNode) MarshalJSON() ([]byte, error) {
+	type plain LinkNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *ImageNode) MarshalJSON() ([]byte, error) {
+	type plain ImageNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *VideoNode) MarshalJSON() ([]byte, error) {
+	type plain VideoNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *AudioNode) MarshalJSON() ([]byte, error) {
+	type plain AudioNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *EmbedNode) MarshalJSON() ([]byte, error) {
+	type plain EmbedNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *FigureNode) MarshalJSON() ([]byte, error) {
+	type plain FigureNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *DetailsNode) MarshalJSON() ([]byte, error) {
+	type plain DetailsNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *ListNode) MarshalJSON() ([]byte, error) {
+	type plain ListNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *ListItemNode) MarshalJSON() ([]byte, error) {
+	type plain ListItemNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *ListItemNode) UnmarshalJSON(data []byte) error {
+	type plain ListItemNode
+	return decodeNode(data, (*plain)(n), nodeListFields(n))
+}
+
+func (n *DefinitionListNode) MarshalJSON() ([]byte, error) {
+	type plain DefinitionListNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *DefinitionItemNode) MarshalJSON() ([]byte, error) {
+	type plain DefinitionItemNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *DefinitionItemNode) UnmarshalJSON(data []byte) error {
+	type plain DefinitionItemNode
+	return decodeNode(data, (*plain)(n), nodeListFields(n))
+}
+
+func (n *TableNode) MarshalJSON() ([]byte, error) {
+	type plain TableNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *TableRowNode) MarshalJSON() ([]byte, error) {
+	type plain TableRowNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *TableCellNode) MarshalJSON() ([]byte, error) {
+	type plain TableCellNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *TableCellNode) UnmarshalJSON(data []byte) error {
+	type plain TableCellNode
+	return decodeNode(data, (*plain)(n), nodeListFields(n))
+}
+
+func (n *CodeNode) MarshalJSON() ([]byte, error) {
+	type plain CodeNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *MathNode) MarshalJSON() ([]byte, error) {
+	type plain MathNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *ThematicBreakNode) MarshalJSON() ([]byte, error) {
+	type plain ThematicBreakNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *FootnoteReferenceNode) MarshalJSON() ([]byte, error) {
+	type plain FootnoteReferenceNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *FootnoteNode) MarshalJSON() ([]byte, error) {
+	type plain FootnoteNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *ParagraphNode) MarshalJSON() ([]byte, error) {
+	type plain ParagraphNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *BlockquoteNode) MarshalJSON() ([]byte, error) {
+	type plain BlockquoteNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *SemanticHTMLNode) MarshalJSON() ([]byte, error) {
+	type plain SemanticHTMLNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *MetaDataNode) MarshalJSON() ([]byte, error) {
+	type plain MetaDataNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
+
+func (n *CustomNode) MarshalJSON() ([]byte, error) {
+	type plain CustomNode
+	return marshalTyped(n.Type(), (*plain)(n))
+}
@@ -7,54 +7,74 @@ type Node interface {
 }
 
 // TextNode represents plain text content.
-// NOTE: Empty text nodes (whitespace-only) are filtered out during parsing.
+// Whitespace is collapsed as in CSS white-space: normal: runs become a
+// single space, kept where they separate words across inline elements and
+// dropped at the start and end of blocks and lines. Non-breaking spaces are
+// content and kept as U+00A0.
+// Preformatted text from white-space: pre, pre-wrap or break-spaces
+// elements keeps its whitespace as is.
 type TextNode struct {
-	Content string
+	Content      string `json:"content"`
+	Preformatted bool   `json:"preformatted,omitempty"`
 }
 
 func (n *TextNode) Type() string { return "text" }
 
 // BoldNode represents bold/strong text.
 type BoldNode struct {
-	Content []Node
+	Content []Node `json:"content,omitempty"`
 }
 
 func (n *BoldNode) Type() string { return "bold" }
 
 // ItalicNode represents italic/emphasized text.
 type ItalicNode struct {
-	Content []Node
+	Content []Node `json:"content,omitempty"`
 }
 
 func (n *ItalicNode) Type() string { return "italic" }
 
 // StrikethroughNode represents strikethrough text.
 type StrikethroughNode struct {
-	Content []Node
+	Content []Node `json:"content,omitempty"`
 }
 
 func (n *StrikethroughNode) Type() string { return "strikethrough" }
 
+// SuperscriptNode represents superscript text (<sup>).
+type SuperscriptNode struct {
+	Content []Node `json:"content,omitempty"`
+}
+
+func (n *SuperscriptNode) Type() string { return "superscript" }
+
+// SubscriptNode represents subscript text (<sub>).
+type SubscriptNode struct {
+	Content []Node `json:"content,omitempty"`
+}
+
+func (n *SubscriptNode) Type() string { return "subscript" }
+
 // HeadingNode represents headings h1-h6.
 type HeadingNode struct {
-	Level   int // 1-6
-	Content []Node
+	Level   int    `json:"level"` // 1-6
+	Content []Node `json:"content,omitempty"`
 }
 
 func (n *HeadingNode) Type() string { return "heading" }
 
 // LinkNode represents hyperlinks.
 type LinkNode struct {
-	Href    string
-	Content []Node
+	Href    string `json:"href"`
+	Content []Node `json:"content,omitempty"`
 }
 
 func (n *LinkNode) Type() string { return "link" }
 
 // ImageNode represents images.
 type ImageNode struct {
-	Src string
-	Alt string
+	Src string `json:"src"`
+	Alt string `json:"alt,omitempty"`
 }
 
 func (n *ImageNode) Type() string { return "image" }
@@ -66,51 +86,127 @@ func (n *ImageNode) Type() string { return "image" }
 //	![Poster](poster)    // only if poster exists
 //	Controls: true       // only if controls defined
 type VideoNode struct {
-	Src      string
-	Poster   string
-	Controls bool
+	Src      string `json:"src"`
+	Poster   string `json:"poster,omitempty"`
+	Controls bool   `json:"controls,omitempty"`
 }
 
 func (n *VideoNode) Type() string { return "video" }
 
+// AudioNode represents audio elements.
+// Renders as:
+//
+//	![Audio](src)
+//	Controls: true       // only if controls defined
+type AudioNode struct {
+	Src      string `json:"src"`
+	Controls bool   `json:"controls,omitempty"`
+}
+
+func (n *AudioNode) Type() string { return "audio" }
+
+// EmbedNode represents embedded documents and players from <iframe>,
+// <embed> and <object>. Renders as a link to the embedded resource:
+//
+//	[title](src)
+type EmbedNode struct {
+	Src   string `json:"src"`
+	Title string `json:"title,omitempty"`
+	Tag   string `json:"tag,omitempty"` // iframe, embed or object
+}
+
+func (n *EmbedNode) Type() string { return "embed" }
+
+// FigureNode represents a <figure>: its content (usually an image) paired
+// with the <figcaption>.
+type FigureNode struct {
+	Content []Node `json:"content,omitempty"`
+	Caption []Node `json:"caption,omitempty"`
+}
+
+func (n *FigureNode) Type() string { return "figure" }
+
+// DetailsNode represents a <details> disclosure widget: the <summary> and
+// the body shown when expanded.
+type DetailsNode struct {
+	Summary []Node `json:"summary,omitempty"`
+	Content []Node `json:"content,omitempty"`
+	Open    bool   `json:"open,omitempty"` // <details open>
+}
+
+func (n *DetailsNode) Type() string { return "details" }
+
 // ListNode represents ordered or unordered lists.
+// Start and Reversed mirror <ol start> and <ol reversed>; a nil Start
+// numbers from 1, or down from the item count when Reversed.
 type ListNode struct {
-	Ordered bool
-	Items   []ListItemNode
+	Ordered  bool           `json:"ordered,omitempty"`
+	Start    *int           `json:"start,omitempty"`
+	Reversed bool           `json:"reversed,omitempty"`
+	Items    []ListItemNode `json:"items,omitempty"`
 }
 
 func (n *ListNode) Type() string { return "list" }
 
 // ListItemNode represents a list item.
+// Content may mix inline and block nodes (nested lists, code, ...).
 type ListItemNode struct {
-	Content []Node
+	Content []Node `json:"content,omitempty"`
+	Value   *int   `json:"value,omitempty"`   // <li value>: number of this item, later items continue from it
+	IsTask  bool   `json:"isTask,omitempty"`  // True if the item starts with a checkbox
+	Checked bool   `json:"checked,omitempty"` // Checkbox state of a task item
 }
 
 func (n *ListItemNode) Type() string { return "listItem" }
 
+// DefinitionListNode represents definition lists (<dl>).
+// Items keep document order; each term is followed by its definitions.
+type DefinitionListNode struct {
+	Items []DefinitionItemNode `json:"items,omitempty"`
+}
+
+func (n *DefinitionListNode) Type() string { return "definitionList" }
+
+// DefinitionItemNode represents a definition list term (<dt>) or definition (<dd>).
+type DefinitionItemNode struct {
+	Content []Node `json:"content,omitempty"`
+	IsTerm  bool   `json:"isTerm,omitempty"` // True for <dt>, false for <dd>
+}
+
+func (n *DefinitionItemNode) Type() string { return "definitionItem" }
+
 // TableNode represents tables.
+// Rows holds the header row (if any) and body rows; <tfoot> rows are kept
+// separately in FooterRows and render after the body.
 type TableNode struct {
-	Rows      []TableRowNode
-	ColIDs    []string // Column IDs for tracking
-	HasHeader bool     // True if first row contains <th> cells
+	Rows       []TableRowNode `json:"rows,omitempty"`
+	FooterRows []TableRowNode `json:"footerRows,omitempty"`
+	Caption    []Node         `json:"caption,omitempty"`   // <caption> content
+	ColIDs     []string       `json:"colIds,omitempty"`    // Column IDs for tracking
+	HasHeader  bool           `json:"hasHeader,omitempty"` // True if the first row is a header (<thead> or <th> cells)
 }
 
 func (n *TableNode) Type() string { return "table" }
 
 // TableRowNode represents a table row.
 type TableRowNode struct {
-	Cells []TableCellNode
+	Cells []TableCellNode `json:"cells,omitempty"`
 }
 
 func (n *TableRowNode) Type() string { return "tableRow" }
 
 // TableCellNode represents a table cell.
 type TableCellNode struct {
-	Content  []Node
-	ColID    string
-	Colspan  int
-	Rowspan  int
-	IsHeader bool // True for <th>, false for <td>
+	Content  []Node   `json:"content,omitempty"`
+	ColID    string   `json:"colId,omitempty"`
+	Colspan  int      `json:"colspan,omitempty"`
+	Rowspan  int      `json:"rowspan,omitempty"`
+	IsHeader bool     `json:"isHeader,omitempty"` // True for <th>, false for <td>
+	Align    string   `json:"align,omitempty"`    // left, center, right, or empty for default
+	Spanned  bool     `json:"spanned,omitempty"`  // True for a grid position covered by another cell's span
+	Scope    string   `json:"scope,omitempty"`    // row, col, rowgroup, colgroup; "row" for row header cells
+	ID       string   `json:"id,omitempty"`       // id attribute, referenced by Headers of other cells
+	Headers  []string `json:"headers,omitempty"`  // ids of the header cells for this cell
 }
 
 func (n *TableCellNode) Type() string { return "tableCell" }
@@ -118,47 +214,91 @@ func (n *TableCellNode) Type() string { return "tableCell" }
 // CodeNode represents code (inline or block).
 // NOTE: Content inside code blocks is NOT escaped.
 type CodeNode struct {
-	Content  string
-	Language string
-	Inline   bool
+	Content  string `json:"content"`
+	Language string `json:"language,omitempty"`
+	Inline   bool   `json:"inline,omitempty"`
 }
 
 func (n *CodeNode) Type() string { return "code" }
 
+// MathNode represents a formula from MathML, KaTeX or MathJax as TeX.
+// Renders as "$tex$" inline or as a "$$" block when Display is set.
+// NOTE: TeX is NOT escaped.
+type MathNode struct {
+	TeX     string `json:"tex"`
+	Display bool   `json:"display,omitempty"`
+}
+
+func (n *MathNode) Type() string { return "math" }
+
+// ThematicBreakNode represents a horizontal rule (<hr>).
+// Renders as "---".
+type ThematicBreakNode struct{}
+
+func (n *ThematicBreakNode) Type() string { return "thematicBreak" }
+
+// FootnoteReferenceNode represents an in-page citation link to a footnote.
+// Renders as "[^Label]".
+type FootnoteReferenceNode struct {
+	Label string `json:"label"`
+}
+
+func (n *FootnoteReferenceNode) Type() string { return "footnoteReference" }
+
+// FootnoteNode represents a footnote definition taken from a references list.
+// Renders as "[^Label]: {content}".
+type FootnoteNode struct {
+	Label   string `json:"label"`
+	ID      string `json:"id,omitempty"` // Anchor id that references link to
+	Content []Node `json:"content,omitempty"`
+}
+
+func (n *FootnoteNode) Type() string { return "footnote" }
+
+// ParagraphNode represents a <p> that shares its container with other
+// content. A <p> that is the only content of its container is not wrapped.
+type ParagraphNode struct {
+	Content []Node `json:"content,omitempty"`
+}
+
+func (n *ParagraphNode) Type() string { return "paragraph" }
+
 // BlockquoteNode represents blockquotes.
 type BlockquoteNode struct {
-	Content []Node
+	Content []Node `json:"content,omitempty"`
 }
 
 func (n *BlockquoteNode) Type() string { return "blockquote" }
 
 // SemanticHTMLNode represents semantic HTML elements.
-// Rendering behavior (matching Node.js):
+// Rendering is controlled per tag by a SemanticPolicy; by default
+// (matching Node.js):
 //   - article: renders content directly, no wrapper
 //   - section: wrapped with "---\n\n{content}\n\n---\n"
-//   - all others (aside, nav, header, footer, main, figure, figcaption,
-//     details, summary, mark, time): wrapped in HTML comments
+//   - all others (aside, nav, header, footer, main, figcaption, summary,
+//     mark, time): wrapped in HTML comments
 //     "<!-- <tag> -->\n{content}\n<!-- </tag> -->\n"
 type SemanticHTMLNode struct {
-	HTMLType string // article, aside, details, figcaption, figure, footer, header, main, mark, nav, section, summary, time
-	Content  []Node
+	HTMLType string `json:"htmlType"`        // article, aside, figcaption, footer, header, main, mark, nav, section, summary, time
+	Label    string `json:"label,omitempty"` // aria-label or title attribute
+	Content  []Node `json:"content,omitempty"`
 }
 
 func (n *SemanticHTMLNode) Type() string { return "semanticHtml" }
 
 // MetaDataNode represents extracted page metadata.
 type MetaDataNode struct {
-	Standard  map[string]string // title, description, keywords (sorted alphabetically on output)
-	OpenGraph map[string]string // og:* tags (sorted alphabetically on output)
-	Twitter   map[string]string // twitter:* tags (sorted alphabetically on output)
-	JSONLD    []map[string]any  // JSON-LD structured data
+	Standard  map[string]string `json:"standard,omitempty"`  // title, description, keywords (sorted alphabetically on output)
+	OpenGraph map[string]string `json:"openGraph,omitempty"` // og:* tags (sorted alphabetically on output)
+	Twitter   map[string]string `json:"twitter,omitempty"`   // twitter:* tags (sorted alphabetically on output)
+	JSONLD    []map[string]any  `json:"jsonLd,omitempty"`    // JSON-LD structured data
 }
 
 func (n *MetaDataNode) Type() string { return "meta" }
 
 // CustomNode for user-defined content.
 type CustomNode struct {
-	Content any
+	Content any `json:"content,omitempty"`
 }
 
 func (n *CustomNode) Type() string { return "custom" }
@@ -2,10 +2,16 @@ package types
 
 // ConversionOptions configures the HTML to Markdown conversion.
 type ConversionOptions struct {
-	// WebsiteDomain is stored for reference but does NOT resolve relative URLs.
-	// Relative URLs are preserved as-is to keep tokens sparse.
+	// WebsiteDomain is the base used to resolve relative URLs when ResolveURLs
+	// is enabled. A scheme-less domain is assumed to be https.
 	WebsiteDomain string
 
+	// ResolveURLs rewrites relative link, image and video URLs to absolute URLs.
+	// A <base href> in the document takes precedence over WebsiteDomain.
+	// When disabled (default), relative URLs are preserved as-is to keep tokens sparse.
+	// Resolution happens before RefifyURLs, so resolved URLs are refified as well.
+	ResolveURLs bool
+
 	// ExtractMainContent enables intelligent main content detection.
 	ExtractMainContent bool
 
@@ -28,9 +34,75 @@ type ConversionOptions struct {
 	// Values: "smart" (default), "disabled"
 	EscapeMode EscapeMode
 
+	// Footnotes converts in-page citation links whose target is an item of a
+	// references/footnotes list (e.g. Wikipedia's <ol class="references">)
+	// into GFM footnotes: "[^1]" references and "[^1]: ..." definitions.
+	Footnotes bool
+
+	// TableSpans controls how rowspan/colspan cells are laid out.
+	// Values: "annotate" (default), "duplicate", "empty"
+	TableSpans TableSpanMode
+
+	// TableFormat controls how tables are rendered.
+	// Values: "pipe" (default), "html", "records", "csv", "json", "auto"
+	TableFormat TableFormat
+
+	// DefinitionListStyle controls how definition lists (<dl>) are rendered.
+	// Values: "bold" (default), "extra"
+	DefinitionListStyle DefinitionListStyle
+
+	// FigureCaptionStyle controls how a <figcaption> is paired with its figure.
+	// Values: "italic" (default), "alt"
+	FigureCaptionStyle FigureCaptionStyle
+
+	// DetailsStyle controls how <details>/<summary> disclosure widgets render.
+	// Values: "html" (default), "bold", "heading"
+	DetailsStyle DetailsStyle
+
+	// SemanticPreset selects the rendering policies of semantic elements
+	// (article, section, nav, aside, ...).
+	// Values: "default", "minimal", "structure"
+	SemanticPreset SemanticPreset
+
+	// SemanticElements overrides the preset's policy for individual tags,
+	// keyed by tag name in any case (e.g. {"nav": SemanticDrop}). Only the
+	// semantic elements article, section, aside, nav, header, footer, main,
+	// figcaption, summary, mark and time are accepted; <details> and
+	// <figure> have their own DetailsStyle and FigureCaptionStyle options.
+	SemanticElements map[string]SemanticPolicy
+
+	// ScriptStyle controls how superscript (<sup>) and subscript (<sub>) render.
+	// Values: "caret" (default), "html", "unicode"
+	ScriptStyle ScriptStyle
+
+	// MaxInputBytes limits the size of the HTML input read from a reader.
+	// Zero means unlimited. Exceeding it returns ErrInputTooLarge.
+	MaxInputBytes int64
+
+	// MaxDepth limits the element nesting depth of the HTML document.
+	// Zero means unlimited. Exceeding it returns ErrMaxDepthExceeded.
+	MaxDepth int
+
+	// MaxNodes limits the number of HTML nodes (elements, text, comments).
+	// Zero means unlimited. Exceeding it returns ErrMaxNodesExceeded.
+	MaxNodes int
+
+	// MaxOutputBytes limits the size of the rendered Markdown.
+	// Zero means unlimited. Exceeding it returns ErrOutputTooLarge.
+	MaxOutputBytes int
+
 	// OverrideElementProcessing allows custom element handling during parsing.
 	OverrideElementProcessing ElementProcessor
 
+	// DetectCodeLanguage determines the language of code blocks before the
+	// built-in detection from highlighter classes and data-lang attributes.
+	DetectCodeLanguage CodeLanguageDetector
+
+	// GuessCodeLanguage falls back to guessing the language of a code block
+	// from its content (shebangs, keywords, markup) when no highlighter
+	// convention names it.
+	GuessCodeLanguage bool
+
 	// ProcessUnhandledElement handles unknown HTML elements.
 	ProcessUnhandledElement ElementProcessor
 
@@ -61,3 +133,125 @@ const (
 	EscapeModeSmart    EscapeMode = "smart"
 	EscapeModeDisabled EscapeMode = "disabled"
 )
+
+// TableSpanMode controls how table cells spanning several rows or columns are laid out.
+type TableSpanMode string
+
+const (
+	// TableSpanAnnotate keeps one cell per spanning cell, annotated with
+	// <!-- colspan: N --> / <!-- rowspan: N --> comments.
+	TableSpanAnnotate TableSpanMode = "annotate"
+	// TableSpanDuplicate normalizes the table to a full grid, repeating the
+	// spanning cell's content in every position it covers.
+	TableSpanDuplicate TableSpanMode = "duplicate"
+	// TableSpanEmpty normalizes the table to a full grid, leaving the
+	// positions covered by a span empty.
+	TableSpanEmpty TableSpanMode = "empty"
+)
+
+// TableFormat controls the output format of tables.
+type TableFormat string
+
+const (
+	// TableFormatPipe renders GFM pipe tables.
+	TableFormatPipe TableFormat = "pipe"
+	// TableFormatHTML renders tables as HTML, preserving spans and block content.
+	TableFormatHTML TableFormat = "html"
+	// TableFormatRecords renders each row as a list item of "Header: value" lines.
+	TableFormatRecords TableFormat = "records"
+	// TableFormatCSV renders tables as CSV in a fenced code block.
+	TableFormatCSV TableFormat = "csv"
+	// TableFormatJSON renders tables as a JSON array in a fenced code block:
+	// one object per row keyed by header text, or one array per row without a header.
+	TableFormatJSON TableFormat = "json"
+	// TableFormatAuto picks a format per table: HTML for nested tables,
+	// block content in cells and spans, records for very wide tables, pipe
+	// otherwise.
+	TableFormatAuto TableFormat = "auto"
+)
+
+// DefinitionListStyle controls how definition lists are rendered.
+type DefinitionListStyle string
+
+const (
+	// DefinitionListBold renders a bold term followed by indented definitions.
+	DefinitionListBold DefinitionListStyle = "bold"
+	// DefinitionListExtra renders PHP Markdown Extra syntax ("Term\n: Definition").
+	DefinitionListExtra DefinitionListStyle = "extra"
+)
+
+// FigureCaptionStyle controls how figure captions are rendered.
+type FigureCaptionStyle string
+
+const (
+	// FigureCaptionItalic renders the caption as an italic line below the figure.
+	FigureCaptionItalic FigureCaptionStyle = "italic"
+	// FigureCaptionAlt uses the caption as alt text of a figure image without
+	// one, falling back to an italic line when there is no such image.
+	FigureCaptionAlt FigureCaptionStyle = "alt"
+)
+
+// DetailsStyle controls how <details> elements are rendered.
+type DetailsStyle string
+
+const (
+	// DetailsHTML keeps a GFM-compatible <details><summary> HTML block with
+	// the body as Markdown.
+	DetailsHTML DetailsStyle = "html"
+	// DetailsBold renders the summary as a bold line followed by the body,
+	// e.g. for FAQ questions and answers.
+	DetailsBold DetailsStyle = "bold"
+	// DetailsHeading renders the summary as a level-3 heading followed by the body.
+	DetailsHeading DetailsStyle = "heading"
+)
+
+// SemanticPolicy controls how a semantic element is rendered. Besides the
+// constants below, any value containing "{content}" is a custom template:
+// "{content}", "{tag}" and "{label}" are replaced by the rendered content,
+// the tag name and the element's aria-label or title.
+type SemanticPolicy string
+
+const (
+	// SemanticUnwrap renders the content without a wrapper.
+	SemanticUnwrap SemanticPolicy = "unwrap"
+	// SemanticComment wraps the content in "<!-- <tag> -->" comments.
+	SemanticComment SemanticPolicy = "comment"
+	// SemanticRule wraps the content in horizontal rules ("---").
+	SemanticRule SemanticPolicy = "rule"
+	// SemanticDrop removes the element and its content.
+	SemanticDrop SemanticPolicy = "drop"
+	// SemanticHeading renders a level-2 heading with the element's label (or
+	// its capitalized tag name) followed by the content.
+	SemanticHeading SemanticPolicy = "heading"
+	// SemanticBlockquote renders the content as a blockquote.
+	SemanticBlockquote SemanticPolicy = "blockquote"
+)
+
+// SemanticPreset is a named set of semantic element policies.
+type SemanticPreset string
+
+const (
+	// SemanticPresetDefault unwraps article, wraps section in horizontal
+	// rules and wraps every other element in HTML comments.
+	SemanticPresetDefault SemanticPreset = "default"
+	// SemanticPresetMinimal spends the fewest tokens: nav is dropped and
+	// every other element is unwrapped.
+	SemanticPresetMinimal SemanticPreset = "minimal"
+	// SemanticPresetStructure keeps the document structure visible: landmark
+	// and sectioning elements get comment wrappers, aside becomes a
+	// blockquote and inline elements (mark, time) are unwrapped.
+	SemanticPresetStructure SemanticPreset = "structure"
+)
+
+// ScriptStyle controls how superscript and subscript text is rendered.
+type ScriptStyle string
+
+const (
+	// ScriptStyleCaret renders Pandoc syntax: x^2^ and H~2~O.
+	ScriptStyleCaret ScriptStyle = "caret"
+	// ScriptStyleHTML passes through <sup> and <sub> tags.
+	ScriptStyleHTML ScriptStyle = "html"
+	// ScriptStyleUnicode renders Unicode super/subscript characters (x², H₂O),
+	// falling back to caret syntax when a character has no Unicode form.
+	ScriptStyleUnicode ScriptStyle = "unicode"
+)
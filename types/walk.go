@@ -0,0 +1,170 @@
+package types
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order. It starts by calling
+// v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor w
+// for each child of node, followed by a call of w.Visit(nil).
+//
+// List items, table rows, table cells and definition items are visited as
+// *ListItemNode, *TableRowNode, *TableCellNode and *DefinitionItemNode
+// pointing into their parent, so they can be modified in place.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	forEachChild(node, func(child Node) {
+		Walk(v, child)
+	})
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if node != nil && f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses each node in nodes in depth-first order, calling f(node)
+// for every node. If f returns true, Inspect continues with the children of node.
+func Inspect(nodes []Node, f func(Node) bool) {
+	for _, node := range nodes {
+		if node != nil {
+			Walk(inspector(f), node)
+		}
+	}
+}
+
+// Rewrite transforms an AST bottom-up and returns the new top-level slice.
+// For every node held in a Content slice (and every top-level node), the
+// children are rewritten first, then f(node) is called and its result
+// replaces the node: return []Node{node} to keep it, nil to remove it, or
+// any other nodes to substitute them.
+//
+// List items, table rows, table cells and definition items are structural
+// and are not passed to f; their content is rewritten. Container nodes are
+// updated in place with their rewritten Content; the input slice itself is
+// not modified.
+func Rewrite(nodes []Node, f func(Node) []Node) []Node {
+	result := make([]Node, 0, len(nodes))
+	for _, node := range nodes {
+		if node == nil {
+			continue
+		}
+		rewriteChildren(node, f)
+		result = append(result, f(node)...)
+	}
+	return result
+}
+
+func rewriteChildren(node Node, f func(Node) []Node) {
+	content, items := children(node)
+	for _, slice := range content {
+		*slice = Rewrite(*slice, f)
+	}
+	for _, item := range items {
+		rewriteChildren(item, f)
+	}
+}
+
+// forEachChild calls fn for each direct child of node.
+func forEachChild(node Node, fn func(Node)) {
+	content, items := children(node)
+	for _, slice := range content {
+		for _, child := range *slice {
+			if child != nil {
+				fn(child)
+			}
+		}
+	}
+	for _, item := range items {
+		fn(item)
+	}
+}
+
+// children describes the direct children of node: the slices of child nodes
+// it holds, and the structural items (list items, table rows and cells,
+// definition items) pointing into it. It is the single source of truth for
+// the AST's child structure, shared by Walk and Rewrite.
+func children(node Node) (content []*[]Node, items []Node) {
+	switch n := node.(type) {
+	case *ListNode:
+		for i := range n.Items {
+			items = append(items, &n.Items[i])
+		}
+	case *TableNode:
+		content = []*[]Node{&n.Caption}
+		for i := range n.Rows {
+			items = append(items, &n.Rows[i])
+		}
+		for i := range n.FooterRows {
+			items = append(items, &n.FooterRows[i])
+		}
+	case *TableRowNode:
+		for i := range n.Cells {
+			items = append(items, &n.Cells[i])
+		}
+	case *DefinitionListNode:
+		for i := range n.Items {
+			items = append(items, &n.Items[i])
+		}
+	case *FigureNode:
+		content = []*[]Node{&n.Content, &n.Caption}
+	case *DetailsNode:
+		content = []*[]Node{&n.Summary, &n.Content}
+	default:
+		if slice := contentOf(node); slice != nil {
+			content = []*[]Node{slice}
+		}
+	}
+	return content, items
+}
+
+// contentOf returns a pointer to the Content slice of nodes that hold
+// child nodes directly, or nil for leaf and structural nodes.
+func contentOf(node Node) *[]Node {
+	switch n := node.(type) {
+	case *BoldNode:
+		return &n.Content
+	case *ItalicNode:
+		return &n.Content
+	case *StrikethroughNode:
+		return &n.Content
+	case *SuperscriptNode:
+		return &n.Content
+	case *SubscriptNode:
+		return &n.Content
+	case *HeadingNode:
+		return &n.Content
+	case *LinkNode:
+		return &n.Content
+	case *ListItemNode:
+		return &n.Content
+	case *TableCellNode:
+		return &n.Content
+	case *DefinitionItemNode:
+		return &n.Content
+	case *FootnoteNode:
+		return &n.Content
+	case *ParagraphNode:
+		return &n.Content
+	case *BlockquoteNode:
+		return &n.Content
+	case *SemanticHTMLNode:
+		return &n.Content
+	default:
+		// TextNode, ImageNode, VideoNode, AudioNode, EmbedNode, CodeNode,
+		// MathNode, ThematicBreakNode, FootnoteReferenceNode, MetaDataNode and
+		// CustomNode are leaves
+		return nil
+	}
+}